@@ -16,6 +16,7 @@ package main
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/FishGoddess/logit"
 )
@@ -40,4 +41,29 @@ func main() {
 	// Then you can get the logger from context.
 	logger = logit.FromContext(ctx)
 	logger.Debug("logger from context debug", "key", "value")
+
+	// Use DebugContext and other context methods to pass context to the handler.
+	// This is useful if your handler reads some values like trace id from context.
+	logger.DebugContext(ctx, "logger with context debug", "key", "value")
+	logit.InfoContext(ctx, "default logger with context info", "key", "value")
+
+	// Use WithContextAttrs to carry some attrs in context, like request id.
+	// All logs logged with this context will carry these attrs.
+	ctx = logit.WithContextAttrs(ctx, "request_id", "b2f1c8a0")
+	logger.InfoContext(ctx, "logger with context attrs info")
+
+	// If your values are stored in context by other libraries, use WithContextExtractor to extract them.
+	type tenantKey struct{}
+
+	extractor := func(ctx context.Context) []slog.Attr {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []slog.Attr{slog.String("tenant", tenant)}
+		}
+
+		return nil
+	}
+
+	logger = logit.NewLogger(logit.WithContextExtractor(extractor))
+	ctx = context.WithValue(ctx, tenantKey{}, "fishgoddess")
+	logger.InfoContext(ctx, "logger with context extractor info")
 }
@@ -16,6 +16,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"log/slog"
 	"time"
 
@@ -38,6 +39,13 @@ func main() {
 
 	logit.Print("println log is debug level now")
 
+	// Some libraries log by slog.Default() or log package, use SetGlobalDefault to take over their logs.
+	// Logs from log package use the level of old-school logging methods.
+	logit.SetGlobalDefault(logit.NewLogger())
+
+	slog.Info("slog info is logged by logit")
+	log.Printf("log printf is logged by logit")
+
 	// More fields see defaults package.
 	defaults.HandleError = func(label string, err error) {
 		fmt.Printf("%s: %+n\n", label, err)
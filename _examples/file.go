@@ -45,6 +45,13 @@ func main() {
 
 	logger.Debug("debug to rotate file with rotate options")
 
+	// Logger.Sync flushes buffers and then syncs the file to disk by default.
+	// Use WithDurability if you want to sync in interval or after every record, like audit logs which must survive power loss.
+	logger = logit.NewLogger(logit.WithRotateFile("audit.log"), logit.WithBuffer(4096), logit.WithDurability(logit.FsyncEveryRecord()))
+	defer logger.Close()
+
+	logger.Info("audit log synced to disk")
+
 	// See rotate.File if you want to use this magic in other scenes.
 	file, err := rotate.New("logit.log")
 	if err != nil {
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"context"
 	"io"
 	"log/slog"
 
@@ -48,4 +49,14 @@ func main() {
 	newHandler = func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
 		return slog.NewJSONHandler(w, opts)
 	}
+
+	// Want some levels like trace? Try RegisterLevel.
+	// The registered name will be used by tape, text and json handlers.
+	levelTrace := slog.LevelDebug - 4
+	if err := handler.RegisterLevel(levelTrace, "trace"); err != nil {
+		panic(err)
+	}
+
+	logger = logit.NewLogger(logit.WithLevel(levelTrace))
+	logger.Log(context.Background(), levelTrace, "using trace level")
 }
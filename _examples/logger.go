@@ -15,7 +15,10 @@
 package main
 
 import (
+	"fmt"
 	"io"
+	"log/slog"
+	"strings"
 
 	"github.com/FishGoddess/logit"
 )
@@ -33,6 +36,9 @@ func main() {
 	logger.Info("see what are carried")
 	logger.Error("error carried", "err", io.EOF)
 
+	// Use logit.Err() to log an error, and it will be rendered with its message, type and causes.
+	logger.Error("error wrapped", logit.Err(fmt.Errorf("read config: %w", io.EOF)))
+
 	// Use WithGroup() to group args in logger.
 	// All logs output by this logger will group args.
 	logger = logger.WithGroup("xxx")
@@ -45,6 +51,45 @@ func main() {
 		logger.Debug("debug enabled")
 	}
 
+	// Use SetLevel() to change the level of logger at runtime.
+	// The level is shared by the logger and all loggers derived from it.
+	logger.SetLevel(slog.LevelWarn)
+	logger.Info("info will be ignored", "level", logger.Level())
+	logger.SetLevel(slog.LevelDebug)
+
+	// Use Named() to create a logger for one component.
+	// Use logit.WithLevelOverrides to set different levels for components, like "db" at debug level.
+	overrides := map[string]slog.Level{"db": slog.LevelDebug}
+	logger = logit.NewLogger(logit.WithInfoLevel(), logit.WithLevelOverrides(overrides))
+
+	logger.Named("db").Named("pool").Debug("debug from db pool")
+	logger.Named("http").Debug("debug from http will be ignored")
+
+	// Some apis want an io.Writer or a log.Logger, try Writer() and StdLogger().
+	// Every line written to them will be logged as a record.
+	writer := logger.Writer(slog.LevelWarn)
+	writer.Write([]byte("line from writer\n"))
+	writer.Close()
+
+	stdLogger := logger.StdLogger(slog.LevelError)
+	stdLogger.Println("line from std logger")
+
+	// Use logit.Lazy() or logit.LazyAttrs() for expensive values, and they will be computed only if the level is enabled.
+	// Use Debugf() and others to format msg only if the level is enabled.
+	logger.Debug("lazy value", "value", logit.Lazy(func() any { return strings.Repeat("lazy", 3) }))
+	logger.Debugf("formatted %s", "lazily")
+
+	// If you wrap the logger in your functions, the source of logs will be your functions.
+	// Use WithCallerSkip() to skip some frames, or call logit.Helper() in your functions like testing.T.Helper().
+	logger = logit.NewLogger(logit.WithSource())
+	logger.WithCallerSkip(0).Info("skip 0 frame")
+
+	// Use Recover() to log panics with stack, and logit.Go() runs a goroutine recovering panics in the same way.
+	func() {
+		defer logger.Recover("recovered from panic")
+		panic("something wrong")
+	}()
+
 	// We provide some old-school logging methods.
 	// They are using info level by default.
 	// If you want to change the level, see defaults.LevelPrint.
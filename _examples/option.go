@@ -15,7 +15,11 @@
 package main
 
 import (
+	"context"
+	"log/slog"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/FishGoddess/logit"
 )
@@ -45,10 +49,28 @@ func main() {
 	logit.WithFile("")
 	logit.WithRotateFile("")
 
+	// Write logs to several outputs, and each output has its own level, handler and writer:
+	logit.WithOutputs(
+		logit.Output{Level: slog.LevelDebug, Handler: "tape", Writer: os.Stdout},
+		logit.Output{Level: slog.LevelError, Handler: "json", Writer: os.Stderr},
+	)
+
 	// Some useful flags:
 	logit.WithSource()
 	logit.WithPID()
 
+	// Reduce the amount of logs:
+	logit.WithSampling(time.Second, 100, 10)
+	logit.WithDedup(time.Second)
+
+	// Mask sensitive values, like passwords and tokens:
+	logit.WithRedaction([]string{"*password*", "token"}, []*regexp.Regexp{logit.PatternJWT}, logit.MaskKeepLast4)
+
+	// Hook records before handling them, like adding attrs or dropping noises:
+	logit.WithHooks(func(ctx context.Context, record *slog.Record) bool {
+		return record.Message != "health check"
+	})
+
 	// More options can be found in logit package which have prefix "With".
 	// What's more? We provide a options pack that we think it's useful in production.
 	opts := logit.ProductionOptions()
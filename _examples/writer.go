@@ -15,9 +15,13 @@
 package main
 
 import (
+	"io"
+	"log/slog"
 	"os"
+	"time"
 
 	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/writer"
 )
 
 func main() {
@@ -40,4 +44,30 @@ func main() {
 
 	logger = logit.NewLogger(logit.WithRotateFile("logit.log"))
 	logger.Debug("log to rotate file")
+
+	// Writers can be stacked by middlewares, and the first one wraps the writer directly.
+	// WithBuffer, WithBatch and WithAsync are middlewares too, so you can mix them with yours.
+	logger = logit.NewLogger(logit.WithFile("logit.log"), logit.WithBuffer(64*1024), logit.WithWriterMiddleware(func(w io.Writer) io.Writer {
+		return io.MultiWriter(w, os.Stdout)
+	}))
+
+	logger.Debug("log to buffered file and stdout")
+	logger.Close()
+
+	// Want to ship logs to a log collector? Try WithNetwork with tcp, udp or unix sockets.
+	// It reconnects with backoff and keeps a bounded amount of logs while disconnected, so logging won't fail when the collector is down.
+	logger = logit.NewLogger(logit.WithNetwork("tcp", "127.0.0.1:5140", writer.WithWriteTimeout(time.Second)), logit.WithJsonHandler())
+	logger.Debug("log to network")
+	logger.Close()
+
+	// A slow writer blocks logging goroutines, so try WithAsync to write logs in background.
+	// Logs will be put in a bounded queue, and you can choose what to do when the queue is full.
+	logger = logit.NewLogger(logit.WithFile("logit.log"), logit.WithAsync(
+		writer.WithQueueSize(4096),
+		writer.WithOverflowPolicy(writer.OverflowDropBelowLevel),
+		writer.WithDropLevel(slog.LevelWarn),
+	))
+
+	defer logger.Close()
+	logger.Debug("log to file in background")
 }
@@ -0,0 +1,225 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"runtime"
+	"sync"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// stdLogCallerDepth is the depth of caller in log package.
+	// Skip [runtime.Callers, lineWriter.Write, log.Logger.output, log.Printf].
+	stdLogCallerDepth = 4
+
+	// maxLineSize is the max size of a line written to line writer.
+	// Lines longer than it will be split into several records.
+	maxLineSize = 64 * 1024
+)
+
+// slogHandler is a handler adapting logger to slog.Handler.
+// Records handled by it will go through the logger, so they carry attrs of the logger and run hooks of the logger.
+type slogHandler struct {
+	logger *Logger
+}
+
+// Enabled reports whether the handler should ignore logs whose level is lower than passed level.
+func (sh *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return sh.logger.enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with attrs.
+func (sh *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return sh
+	}
+
+	args := make([]any, 0, len(attrs))
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+
+	return &slogHandler{logger: sh.logger.With(args...)}
+}
+
+// WithGroup returns a new handler with group.
+func (sh *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return sh
+	}
+
+	return &slogHandler{logger: sh.logger.WithGroup(name)}
+}
+
+// Handle handles one record and returns an error if failed.
+func (sh *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	logger := sh.logger
+
+	// The slog logger ignores errors returned by handlers, so report it here.
+	if logger.lifecycle.isClosed() {
+		defaults.HandleError("logit.slogHandler.Handle", ErrLoggerClosed)
+		return ErrLoggerClosed
+	}
+
+	newRecord := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	logger.addLoggerAttrs(ctx, &newRecord)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		newRecord.AddAttrs(attr)
+		return true
+	})
+
+	if logger.withStacktrace && record.Level >= logger.stacktraceLevel {
+		stack := captureStack(1).from(record.PC)
+		newRecord.AddAttrs(slog.Any(keyStack, stack))
+	}
+
+	return logger.handle(ctx, newRecord)
+}
+
+// Slog returns a slog logger sharing the handler with logger.
+// Logs from the slog logger will carry attrs of the logger and run hooks of the logger.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(&slogHandler{logger: l})
+}
+
+// lineWriter is a writer adapting logger to io.Writer.
+// It splits data into lines and every line will be logged as a record in level.
+// Partial lines will be kept until a line break comes or the writer is closed,
+// and lines longer than maxLineSize will be split into several records.
+type lineWriter struct {
+	handler *slogHandler
+	level   slog.Level
+
+	// callerDepth is the depth of caller used to get the source of records.
+	// Zero means records have no source.
+	callerDepth int
+
+	buffer []byte
+	lock   sync.Mutex
+}
+
+func newLineWriter(logger *Logger, level slog.Level, callerDepth int) *lineWriter {
+	lw := &lineWriter{
+		handler:     &slogHandler{logger: logger},
+		level:       level,
+		callerDepth: callerDepth,
+	}
+
+	return lw
+}
+
+func (lw *lineWriter) writeLine(pc uintptr, line []byte) error {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+
+	record := slog.NewRecord(defaults.CurrentTime(), lw.level, string(line), pc)
+	return lw.handler.Handle(context.Background(), record)
+}
+
+// Write writes p to logger line by line and keeps the partial line.
+func (lw *lineWriter) Write(p []byte) (n int, err error) {
+	var pc uintptr
+	if lw.callerDepth > 0 {
+		var pcs [1]uintptr
+		runtime.Callers(lw.callerDepth, pcs[:])
+		pc = pcs[0]
+	}
+
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+
+	if !lw.handler.Enabled(context.Background(), lw.level) {
+		return len(p), nil
+	}
+
+	lw.buffer = append(lw.buffer, p...)
+	data := lw.buffer
+
+	defer func() {
+		// Move the partial line to the front so the buffer can be reused.
+		lw.buffer = append(lw.buffer[:0], data...)
+	}()
+
+	for len(data) > 0 {
+		index := bytes.IndexByte(data, '\n')
+		if index < 0 && len(data) < maxLineSize {
+			break
+		}
+
+		next := index + 1
+		if index < 0 || index > maxLineSize {
+			index = maxLineSize
+			next = maxLineSize
+		}
+
+		line := data[:index]
+		data = data[next:]
+
+		if err = lw.writeLine(pc, line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close writes the partial line to logger if exists.
+func (lw *lineWriter) Close() error {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+
+	if len(lw.buffer) <= 0 {
+		return nil
+	}
+
+	err := lw.writeLine(0, lw.buffer)
+	lw.buffer = lw.buffer[:0]
+	return err
+}
+
+// Writer returns a writer which logs every line written to it as a record in level.
+// It's useful for some apis which want an io.Writer, like exec.Cmd.Stderr.
+// Partial lines will be kept until a line break comes, so remember to close the writer to log the last partial line.
+func (l *Logger) Writer(level slog.Level) io.WriteCloser {
+	return newLineWriter(l, level, 0)
+}
+
+// StdLogger returns a log.Logger which logs every line as a record in level.
+// It's useful for some apis which want a log.Logger, like http.Server.ErrorLog.
+func (l *Logger) StdLogger(level slog.Level) *log.Logger {
+	writer := newLineWriter(l, level, stdLogCallerDepth)
+	return log.New(writer, "", 0)
+}
+
+// SetGlobalDefault sets logger as the default logger of logit, slog and log packages.
+// Logs from slog.Default() and log package will be logged by the logger, so they can be written to the same place.
+// Logs from log package use the print level, see defaults.LevelPrint.
+// Notice that the print level is read when calling this function.
+func SetGlobalDefault(logger *Logger) {
+	SetDefault(logger)
+
+	slog.SetDefault(logger.Slog())
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(newLineWriter(logger, defaults.LevelPrint, stdLogCallerDepth))
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSlog$
+func TestLoggerSlog(t *testing.T) {
+	hook := func(ctx context.Context, record *slog.Record) bool {
+		return record.Message != "dropped"
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(
+		WithInfoLevel(), WithTextHandler(), WithWriter(buffer), WithSource(), WithPID(), WithHooks(hook),
+		WithStacktrace(slog.LevelError),
+	)
+
+	slogLogger := logger.Named("slog").Slog()
+	slogLogger.Debug("debug msg")
+	slogLogger.Info("dropped")
+	slogLogger.With("key", 1).WithGroup("group").Info("info msg", "key", 2)
+
+	got := buffer.String()
+	if strings.Count(got, "\n") != 1 {
+		t.Fatalf("got %s has wrong lines", got)
+	}
+
+	if !strings.Contains(got, "adapter_test.go:") {
+		t.Fatalf("got %s has wrong source", got)
+	}
+
+	want := `level=INFO msg="info msg" key=1 group.pid=` + strconv.Itoa(pid) + ` group.logger=slog group.key=2`
+	if removed := strings.TrimSpace(removeTimeAndSource(got)); removed != want {
+		t.Fatalf("removed %s != want %s", removed, want)
+	}
+
+	buffer.Reset()
+	slogLogger.Error("error msg")
+
+	got = buffer.String()
+	if !strings.Contains(got, `stack="github.com/FishGoddess/logit.TestLoggerSlog `) {
+		t.Fatalf("got %s has wrong stack", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSetGlobalDefault$
+func TestSetGlobalDefault(t *testing.T) {
+	logger := Default()
+	slogLogger := slog.Default()
+
+	defer func() {
+		SetDefault(logger)
+		slog.SetDefault(slogLogger)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	SetGlobalDefault(NewLogger(WithTextHandler(), WithWriter(buffer), WithSource()))
+
+	log.Printf("log %s", "printf")
+	slog.Warn("slog warn", "key", "value")
+	Error("logit error")
+
+	got := buffer.String()
+	if strings.Count(got, "adapter_test.go:") != 3 {
+		t.Fatalf("got %s has wrong source", got)
+	}
+
+	want := `level=INFO msg="log printf" level=WARN msg="slog warn" key=value level=ERROR msg="logit error"`
+	if removed := strings.TrimSpace(removeTimeAndSource(got)); removed != want {
+		t.Fatalf("removed %s != want %s", removed, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWriter$
+func TestLoggerWriter(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithInfoLevel(), WithTextHandler(), WithWriter(buffer))
+
+	writer := logger.Writer(slog.LevelDebug)
+	writer.Write([]byte("ignored\n"))
+
+	if buffer.Len() > 0 {
+		t.Fatalf("buffer.Len() %d > 0", buffer.Len())
+	}
+
+	writer = logger.Writer(slog.LevelWarn)
+	writer.Write([]byte("line1\nli"))
+	writer.Write([]byte("ne2\r\n"))
+	writer.Write([]byte("line3"))
+
+	want := `level=WARN msg=line1 level=WARN msg=line2`
+	if got := strings.TrimSpace(removeTimeAndSource(buffer.String())); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want = want + ` level=WARN msg=line3`
+	if got := strings.TrimSpace(removeTimeAndSource(buffer.String())); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	buffer.Reset()
+
+	longLine := strings.Repeat("x", maxLineSize+10)
+	writer.Write([]byte(longLine + "\n"))
+	writer.Write([]byte(longLine))
+
+	want = strings.Repeat("x", maxLineSize)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("len(lines) %d != 3", len(lines))
+	}
+
+	if !strings.HasSuffix(lines[0], "msg="+want) || !strings.HasSuffix(lines[1], "msg=xxxxxxxxxx") || !strings.HasSuffix(lines[2], "msg="+want) {
+		t.Fatalf("lines %+v is wrong", lines)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(strings.TrimSpace(buffer.String()), "msg=xxxxxxxxxx") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerStdLogger$
+func TestLoggerStdLogger(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithTextHandler(), WithWriter(buffer), WithSource())
+
+	stdLogger := logger.StdLogger(slog.LevelError)
+	stdLogger.Printf("std %s", "printf")
+	stdLogger.Println("std println")
+
+	got := buffer.String()
+	if strings.Count(got, "adapter_test.go:") != 2 {
+		t.Fatalf("got %s has wrong source", got)
+	}
+
+	want := `level=ERROR msg="std printf" level=ERROR msg="std println"`
+	if removed := strings.TrimSpace(removeTimeAndSource(got)); removed != want {
+		t.Fatalf("removed %s != want %s", removed, want)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"github.com/FishGoddess/logit/writer"
+)
+
+// asyncHandler drops records which the async writer doesn't admit before handling them.
+// See writer.OverflowDropBelowLevel.
+type asyncHandler struct {
+	handler slog.Handler
+	writer  *writer.AsyncWriter
+}
+
+// newAsyncHandler returns an async handler of handler if there is an async writer in writers, or returns handler itself.
+// The outermost async writer will be used if there are more than one.
+func newAsyncHandler(handler slog.Handler, writers []io.Writer) slog.Handler {
+	for i := len(writers) - 1; i >= 0; i-- {
+		aw, ok := writers[i].(*writer.AsyncWriter)
+		if !ok {
+			continue
+		}
+
+		ah := &asyncHandler{
+			handler: handler,
+			writer:  aw,
+		}
+
+		return ah
+	}
+
+	return handler
+}
+
+func (ah *asyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return ah.handler.Enabled(ctx, level)
+}
+
+func (ah *asyncHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !ah.writer.Admit(record.Level) {
+		return nil
+	}
+
+	return ah.handler.Handle(ctx, record)
+}
+
+func (ah *asyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &asyncHandler{handler: ah.handler.WithAttrs(attrs), writer: ah.writer}
+}
+
+func (ah *asyncHandler) WithGroup(name string) slog.Handler {
+	return &asyncHandler{handler: ah.handler.WithGroup(name), writer: ah.writer}
+}
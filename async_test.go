@@ -0,0 +1,118 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/FishGoddess/logit/writer"
+)
+
+type testAsyncWriter struct {
+	buffer  bytes.Buffer
+	started chan struct{}
+	unblock chan struct{}
+
+	lock sync.Mutex
+}
+
+func (taw *testAsyncWriter) Write(p []byte) (n int, err error) {
+	select {
+	case taw.started <- struct{}{}:
+	default:
+	}
+
+	<-taw.unblock
+
+	taw.lock.Lock()
+	defer taw.lock.Unlock()
+
+	return taw.buffer.Write(p)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerAsync$
+func TestLoggerAsync(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithBuffer(1024), WithAsync())
+
+	logger.Info("async msg", "key", "value")
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.TrimSpace(buffer.String())
+	want := "INFO ¦ async msg ¦ key=value"
+
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't end with %s", got, want)
+	}
+
+	logger.Warn("closing")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buffer.String(), "WARN ¦ closing") {
+		t.Fatalf("buffer %s doesn't contain the log before closing", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerAsyncDropBelowLevel$
+func TestLoggerAsyncDropBelowLevel(t *testing.T) {
+	w := &testAsyncWriter{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+	asyncOpts := []writer.AsyncOption{
+		writer.WithQueueSize(1), writer.WithOverflowPolicy(writer.OverflowDropBelowLevel), writer.WithDropLevel(slog.LevelWarn),
+	}
+
+	logger := NewLogger(WithWriter(w), WithAsync(asyncOpts...))
+
+	ah, ok := logger.handler.(*asyncHandler)
+	if !ok {
+		t.Fatalf("logger.handler type %T is wrong", logger.handler)
+	}
+
+	// The first log blocks the flusher and the second one fills the queue.
+	logger.Info("info 1")
+	<-w.started
+	logger.Info("info 2")
+
+	logger.With("key", "value").Info("info dropped")
+
+	if ah.writer.Dropped() != 1 {
+		t.Fatalf("ah.writer.Dropped() %d != 1", ah.writer.Dropped())
+	}
+
+	close(w.unblock)
+	logger.Warn("warn kept")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := w.buffer.String()
+	if !strings.Contains(got, "info 1") || !strings.Contains(got, "info 2") || !strings.Contains(got, "warn kept") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if strings.Contains(got, "info dropped") {
+		t.Fatalf("got %s shouldn't contain the dropped log", got)
+	}
+}
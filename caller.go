@@ -0,0 +1,85 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// maxCallerDepth is the max depth of frames to search for a caller which isn't a helper.
+	maxCallerDepth = 32
+)
+
+var (
+	// helpers stores the names of helper functions.
+	helpers    sync.Map
+	hasHelpers atomic.Bool
+)
+
+// Helper marks the calling function as a logging helper function.
+// The source of logs will skip helper functions and use the caller of them instead, like testing.T.Helper.
+// It's useful for wrapper functions around a logger, and it works for all loggers.
+// Helper may be called simultaneously from multiple goroutines.
+func Helper() {
+	var pcs [1]uintptr
+	if runtime.Callers(2, pcs[:]) <= 0 {
+		return
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	if _, ok := helpers.Load(frame.Function); ok {
+		return
+	}
+
+	helpers.Store(frame.Function, struct{}{})
+	hasHelpers.Store(true)
+}
+
+// isHelper reports whether the function of pc is marked as a helper.
+func isHelper(pc uintptr) bool {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	_, ok := helpers.Load(frame.Function)
+	return ok
+}
+
+// callerPC returns the pc of caller and skips some frames and helper functions.
+// The skip is the same as runtime.Callers and the frame of callerPC is skipped too.
+func callerPC(skip int) uintptr {
+	if !hasHelpers.Load() {
+		var pcs [1]uintptr
+		runtime.Callers(skip+1, pcs[:])
+
+		return pcs[0]
+	}
+
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+	if n <= 0 {
+		return 0
+	}
+
+	for _, pc := range pcs[:n] {
+		if !isHelper(pc) {
+			return pc
+		}
+	}
+
+	// All frames are helpers so use the outermost one.
+	return pcs[n-1]
+}
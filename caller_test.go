@@ -0,0 +1,156 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type testSource struct {
+	Source struct {
+		Function string `json:"function"`
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+	} `json:"source"`
+
+	Stack []string `json:"stack"`
+}
+
+func parseTestSources(t *testing.T, buffer *bytes.Buffer) []testSource {
+	var sources []testSource
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var source testSource
+		if err := json.Unmarshal([]byte(line), &source); err != nil {
+			t.Fatal(err)
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources
+}
+
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func checkTestSource(t *testing.T, source testSource, line int) {
+	t.Helper()
+
+	if file := filepath.Base(source.Source.File); file != "caller_test.go" {
+		t.Fatalf("file %s != caller_test.go", file)
+	}
+
+	if source.Source.Line != line {
+		t.Fatalf("line %d != %d", source.Source.Line, line)
+	}
+}
+
+func logWithCallerSkip(logger *Logger, msg string) {
+	logger.WithCallerSkip(1).Info(msg)
+}
+
+func logWithHelper(logger *Logger, msg string) {
+	Helper()
+	logger.Info(msg)
+}
+
+func logWithNestedHelper(logger *Logger, msg string) {
+	Helper()
+	logWithHelper(logger, msg)
+}
+
+func logDefaultWithHelper(msg string) {
+	Helper()
+	Info(msg)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerWithCallerSkip$
+func TestLoggerWithCallerSkip(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	line := currentLine() + 1
+	logWithCallerSkip(logger, "caller skip")
+
+	sources := parseTestSources(t, buffer)
+	checkTestSource(t, sources[0], line)
+
+	if skip := logger.WithCallerSkip(-1).callerSkip; skip != 0 {
+		t.Fatalf("skip %d != 0", skip)
+	}
+
+	if skip := logger.WithCallerSkip(2).WithCallerSkip(-1).callerSkip; skip != 1 {
+		t.Fatalf("skip %d != 1", skip)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHelper$
+func TestHelper(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	defaultLogger := Default()
+	defer SetDefault(defaultLogger)
+
+	SetDefault(logger)
+
+	lines := make([]int, 0, 4)
+
+	lines = append(lines, currentLine()+1)
+	logWithHelper(logger, "helper")
+
+	lines = append(lines, currentLine()+1)
+	logWithNestedHelper(logger, "nested helper")
+
+	lines = append(lines, currentLine()+1)
+	logDefaultWithHelper("default helper")
+
+	lines = append(lines, currentLine()+1)
+	logger.Info("not helper")
+
+	sources := parseTestSources(t, buffer)
+	if len(sources) != len(lines) {
+		t.Fatalf("len(sources) %d != len(lines) %d", len(sources), len(lines))
+	}
+
+	for i, source := range sources {
+		checkTestSource(t, source, lines[i])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHelperStacktrace$
+func TestHelperStacktrace(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithStacktrace(slog.LevelInfo))
+
+	logWithHelper(logger, "helper")
+
+	sources := parseTestSources(t, buffer)
+	if len(sources[0].Stack) <= 0 {
+		t.Fatalf("len(sources[0].Stack) %d <= 0", len(sources[0].Stack))
+	}
+
+	if !strings.HasPrefix(sources[0].Stack[0], "github.com/FishGoddess/logit.TestHelperStacktrace ") {
+		t.Fatalf("sources[0].Stack[0] %s is wrong", sources[0].Stack[0])
+	}
+}
@@ -15,12 +15,15 @@
 package logit
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/FishGoddess/logit/handler"
+	"github.com/FishGoddess/logit/writer"
 )
 
 type nilSyncer struct{}
@@ -35,19 +38,66 @@ func (nilCloser) Close() error {
 	return nil
 }
 
+type multiSyncer []Syncer
+
+func (ms multiSyncer) Sync() error {
+	var errs []error
+	for _, syncer := range ms {
+		if err := syncer.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+type multiCloser []io.Closer
+
+func (mc multiCloser) Close() error {
+	var errs []error
+	for _, closer := range mc {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 type config struct {
-	level   slog.Level
-	handler string
+	level          slog.Level
+	levelOverrides map[string]slog.Level
+	handler        string
 
-	newWriter  func() (io.Writer, error)
-	wrapWriter func(io.Writer) io.Writer
+	newWriter         func() (io.Writer, error)
+	writerMiddlewares []func(io.Writer) io.Writer
+	asyncMiddleware   func(io.Writer) io.Writer
+
+	outputs []Output
 
 	replaceAttr func(groups []string, attr slog.Attr) slog.Attr
+	redactor    *redactor
+
+	contextExtractors []ContextExtractor
+	hooks             []Hook
+
+	dedupWindow time.Duration
+
+	samplingTick       time.Duration
+	samplingFirst      int
+	samplingThereafter int
 
 	withSource bool
 	withPID    bool
 
-	syncTimer time.Duration
+	withStacktrace  bool
+	stacktraceLevel slog.Level
+
+	syncTimer  time.Duration
+	durability DurabilityPolicy
+
+	withShutdownSignals bool
+	shutdownSignals     []os.Signal
 }
 
 func newDefaultConfig() *config {
@@ -56,71 +106,222 @@ func newDefaultConfig() *config {
 	}
 
 	conf := &config{
-		level:       slog.LevelDebug,
-		handler:     handler.Tape,
-		newWriter:   newWriter,
-		wrapWriter:  nil,
-		replaceAttr: nil,
-		withSource:  false,
-		withPID:     false,
-		syncTimer:   0,
+		level:             slog.LevelDebug,
+		levelOverrides:    nil,
+		handler:           handler.Tape,
+		newWriter:         newWriter,
+		writerMiddlewares: nil,
+		asyncMiddleware:   nil,
+		outputs:           nil,
+		replaceAttr:       nil,
+		redactor:          nil,
+		contextExtractors: nil,
+		hooks:             nil,
+		dedupWindow:       0,
+		samplingTick:      0,
+		withSource:        false,
+		withPID:           false,
+		withStacktrace:    false,
+		stacktraceLevel:   slog.LevelError,
+		syncTimer:         0,
+		durability:        FsyncOnSync(),
+
+		withShutdownSignals: false,
+		shutdownSignals:     nil,
 	}
 
 	return conf
 }
 
-func (c *config) newSyncer(handler slog.Handler, writer io.Writer) Syncer {
+// newSyncer returns the handler if it's a syncer, or returns a syncer of all writers in chain.
+// Writers are synced from the outermost to the innermost, so data in outer writers will reach inner writers before syncing them.
+// Whether the innermost writer is synced to stable storage depends on the durability policy.
+func (c *config) newSyncer(handler slog.Handler, writers []io.Writer) Syncer {
 	if syncer, ok := handler.(Syncer); ok {
 		return syncer
 	}
 
-	if syncer, ok := writer.(Syncer); ok {
-		return syncer
+	fsync := c.durability.fsync()
+	if len(writers) == 1 && fsync && notStdoutAndStderr(writers[0]) {
+		if syncer, ok := writers[0].(Syncer); ok {
+			return syncer
+		}
+	}
+
+	for _, w := range writers {
+		_, isSyncer := w.(Syncer)
+		_, isFlusher := w.(writer.Flusher)
+
+		if isSyncer || isFlusher {
+			return chainSyncer{writers: writers, fsync: fsync}
+		}
 	}
 
 	return nilSyncer{}
 }
 
-func (c *config) newCloser(handler slog.Handler, writer io.Writer) io.Closer {
+// newCloser returns the handler if it's a closer, or returns the closers of writers in chain from the outermost to the innermost.
+// A closer in chain should close the writer it wraps, like writer.BufferWriter, so writers wrapped directly by closers are skipped.
+// Writers wrapped by middlewares which aren't closers will be closed by us, except stdout and stderr.
+func (c *config) newCloser(handler slog.Handler, writers []io.Writer) io.Closer {
 	if closer, ok := handler.(io.Closer); ok {
 		return closer
 	}
 
-	if closer, ok := writer.(io.Closer); ok {
-		return closer
+	var closers multiCloser
+	for i := len(writers) - 1; i >= 0; i-- {
+		closer, ok := writers[i].(io.Closer)
+		if !ok || !notStdoutAndStderr(writers[i]) {
+			continue
+		}
+
+		if i < len(writers)-1 {
+			if _, wrapped := writers[i+1].(io.Closer); wrapped {
+				continue
+			}
+		}
+
+		closers = append(closers, closer)
 	}
 
-	return nilCloser{}
+	switch len(closers) {
+	case 0:
+		return nilCloser{}
+	case 1:
+		return closers[0]
+	default:
+		return closers
+	}
 }
 
-func (c *config) newHandlerOptions() *slog.HandlerOptions {
+// syncInterval returns the interval of syncing logger in background or 0 if no need.
+// Only one timer is needed if both sync timer and FsyncInterval are used, so the shorter interval is returned.
+func (c *config) syncInterval() time.Duration {
+	interval := c.syncTimer
+	if c.durability.mode == durabilityInterval && (interval <= 0 || c.durability.interval < interval) {
+		interval = c.durability.interval
+	}
+
+	return interval
+}
+
+func (c *config) newHandlerOptions(level slog.Leveler) *slog.HandlerOptions {
+	replaceAttr := c.replaceAttr
+	if c.redactor != nil {
+		// Redact attrs replaced by users so nothing sensitive will be leaked.
+		replaceAttr = composeReplaceAttr(replaceAttr, c.redactor.replaceAttr)
+	}
+
 	opts := &slog.HandlerOptions{
-		Level:       c.level,
+		Level:       level,
 		AddSource:   c.withSource,
-		ReplaceAttr: c.replaceAttr,
+		ReplaceAttr: replaceAttr,
 	}
 
 	return opts
 }
 
-func (c *config) newHandler() (slog.Handler, Syncer, io.Closer, error) {
-	newHandler, err := handler.Get(c.handler)
+func (c *config) wrapHandler(h slog.Handler, syncer Syncer) (slog.Handler, Syncer) {
+	if c.dedupWindow > 0 {
+		dh := handler.Dedup(h, c.dedupWindow)
+
+		// Summaries in dedup handler should be handled before syncing.
+		h = dh
+		syncer = multiSyncer{dh, syncer}
+	}
+
+	if c.samplingTick > 0 {
+		h = handler.Sampling(h, c.samplingTick, c.samplingFirst, c.samplingThereafter)
+	}
+
+	return h, syncer
+}
+
+// middlewares returns all writer middlewares in order, and the async middleware is always the outermost one.
+// Writers wrapped by the async writer run in its background goroutine, so buffers and batches won't block logging.
+func (c *config) middlewares() []func(io.Writer) io.Writer {
+	if c.asyncMiddleware == nil {
+		return c.writerMiddlewares
+	}
+
+	return append(slices.Clip(c.writerMiddlewares), c.asyncMiddleware)
+}
+
+// newOutput creates a handler with name which writes logs to the writer created by newWriter.
+// The writer will be wrapped by middlewares in order, so the first middleware wraps the writer directly.
+func (c *config) newOutput(name string, newWriter func() (io.Writer, error), middlewares []func(io.Writer) io.Writer, level slog.Leveler) (slog.Handler, Syncer, io.Closer, error) {
+	newHandler, err := handler.Get(name)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	writer, err := c.newWriter()
+	writer, err := newWriter()
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	if c.wrapWriter != nil {
-		writer = c.wrapWriter(writer)
+	// Keep all writers in chain so we can find their capabilities even if they are wrapped.
+	writers := []io.Writer{writer}
+	for _, middleware := range middlewares {
+		if middleware != nil {
+			writer = middleware(writer)
+			writers = append(writers, writer)
+		}
 	}
 
-	opts := c.newHandlerOptions()
+	opts := c.newHandlerOptions(level)
 	handler := newHandler(writer, opts)
-	syncer := c.newSyncer(handler, writer)
-	closer := c.newCloser(handler, writer)
+	syncer := c.newSyncer(handler, writers)
+	closer := c.newCloser(handler, writers)
+
+	// Records dropped by async writer in overflow shouldn't be handled.
+	handler = newAsyncHandler(handler, writers)
+
+	if c.durability.mode == durabilityEveryRecord {
+		handler = &durableHandler{handler: handler, syncer: syncer}
+	}
+
+	return handler, syncer, closer, nil
+}
+
+// newOutputs creates a fanout handler of all outputs.
+// The writers created will be closed if failed.
+func (c *config) newOutputs() (slog.Handler, Syncer, io.Closer, error) {
+	handlers := make([]slog.Handler, 0, len(c.outputs))
+	syncers := make(multiSyncer, 0, len(c.outputs))
+	closers := make(multiCloser, 0, len(c.outputs))
+
+	for _, output := range c.outputs {
+		h, syncer, closer, err := c.newOutput(output.handler(), output.newWriter, output.Wrap, output.Level)
+		if err != nil {
+			closers.Close()
+			return nil, nil, nil, err
+		}
+
+		handlers = append(handlers, h)
+		syncers = append(syncers, syncer)
+		closers = append(closers, closer)
+	}
+
+	return handler.Fanout(handlers...), syncers, closers, nil
+}
+
+func (c *config) newHandler(level slog.Leveler) (slog.Handler, Syncer, io.Closer, error) {
+	var handler slog.Handler
+	var syncer Syncer
+	var closer io.Closer
+	var err error
+
+	if len(c.outputs) > 0 {
+		handler, syncer, closer, err = c.newOutputs()
+	} else {
+		handler, syncer, closer, err = c.newOutput(c.handler, c.newWriter, c.middlewares(), level)
+	}
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	handler, syncer = c.wrapHandler(handler, syncer)
 	return handler, syncer, closer, nil
 }
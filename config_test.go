@@ -19,7 +19,9 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/FishGoddess/logit/handler"
 )
@@ -41,7 +43,7 @@ func TestConfigNewHandlerOptions(t *testing.T) {
 		replaceAttr: replaceAttr,
 	}
 
-	opts := conf.newHandlerOptions()
+	opts := conf.newHandlerOptions(conf.level)
 
 	if opts.Level != conf.level {
 		t.Fatalf("opts.Level %v != conf.level %v", opts.Level, conf.level)
@@ -78,7 +80,7 @@ func TestConfigNewHandler(t *testing.T) {
 		withSource:  true,
 	}
 
-	handler, syncer, closer, err := conf.newHandler()
+	handler, syncer, closer, err := conf.newHandler(conf.level)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -112,3 +114,139 @@ func TestConfigNewHandler(t *testing.T) {
 		t.Fatalf("tcHandler.opts.ReplaceAttr %p != conf.replaceAttr %p", tcHandler.opts.ReplaceAttr, conf.replaceAttr)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMultiSyncer$
+func TestMultiSyncer(t *testing.T) {
+	syncers := []*testSyncer{{synced: false}, {synced: false}}
+	syncer := multiSyncer{syncers[0], syncers[1]}
+
+	if err := syncer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, syncer := range syncers {
+		if !syncer.synced {
+			t.Fatalf("syncers[%d].synced is wrong", i)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMultiCloser$
+func TestMultiCloser(t *testing.T) {
+	closers := []*testCloser{{closed: false}, {closed: false}}
+	closer := multiCloser{closers[0], closers[1]}
+
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, closer := range closers {
+		if !closer.closed {
+			t.Fatalf("closers[%d].closed is wrong", i)
+		}
+	}
+}
+
+type testLayerWriter struct {
+	name   string
+	events *[]string
+}
+
+func (tlw *testLayerWriter) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+func (tlw *testLayerWriter) Sync() error {
+	*tlw.events = append(*tlw.events, "sync "+tlw.name)
+	return nil
+}
+
+func (tlw *testLayerWriter) Close() error {
+	*tlw.events = append(*tlw.events, "close "+tlw.name)
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigNewSyncerAndCloser$
+func TestConfigNewSyncerAndCloser(t *testing.T) {
+	conf := newDefaultConfig()
+	handler := slog.NewTextHandler(io.Discard, nil)
+
+	var events []string
+	file := &testLayerWriter{name: "file", events: &events}
+	buffer := &testLayerWriter{name: "buffer", events: &events}
+	writers := []io.Writer{file, io.Discard, buffer, io.Discard}
+
+	if err := conf.newSyncer(handler, writers).Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conf.newCloser(handler, writers).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"sync buffer", "sync file", "close buffer", "close file"}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("events %+v != want %+v", events, want)
+	}
+
+	// Writers wrapped directly by closers should be closed by the closers.
+	events = nil
+
+	if err := conf.newCloser(handler, []io.Writer{file, buffer}).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want = []string{"close buffer"}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("events %+v != want %+v", events, want)
+	}
+
+	if syncer := conf.newSyncer(handler, writers[:1]); syncer != file {
+		t.Fatalf("syncer %+v != file", syncer)
+	}
+
+	if syncer := conf.newSyncer(handler, []io.Writer{io.Discard}); syncer != (nilSyncer{}) {
+		t.Fatalf("syncer %+v != nilSyncer", syncer)
+	}
+
+	if closer := conf.newCloser(handler, []io.Writer{io.Discard}); closer != (nilCloser{}) {
+		t.Fatalf("closer %+v != nilCloser", closer)
+	}
+
+	// Stdout and stderr can't be synced to stable storage.
+	for _, w := range []io.Writer{os.Stdout, os.Stderr} {
+		syncer := conf.newSyncer(handler, []io.Writer{w})
+		if _, ok := syncer.(*os.File); ok {
+			t.Fatalf("syncer %+v is a file", syncer)
+		}
+
+		if err := syncer.Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigSyncInterval$
+func TestConfigSyncInterval(t *testing.T) {
+	testCases := []struct {
+		syncTimer  time.Duration
+		durability DurabilityPolicy
+		want       time.Duration
+	}{
+		{syncTimer: 0, durability: FsyncOnSync(), want: 0},
+		{syncTimer: time.Second, durability: FsyncOnSync(), want: time.Second},
+		{syncTimer: 0, durability: FsyncInterval(time.Minute), want: time.Minute},
+		{syncTimer: time.Second, durability: FsyncInterval(time.Minute), want: time.Second},
+		{syncTimer: time.Minute, durability: FsyncInterval(time.Second), want: time.Second},
+	}
+
+	for _, testCase := range testCases {
+		conf := newDefaultConfig()
+		conf.syncTimer = testCase.syncTimer
+		conf.durability = testCase.durability
+
+		if got := conf.syncInterval(); got != testCase.want {
+			t.Fatalf("got %v != want %v", got, testCase.want)
+		}
+	}
+}
@@ -16,10 +16,17 @@ package logit
 
 import (
 	"context"
+	"log/slog"
 )
 
 type contextKey struct{}
 
+type contextAttrsKey struct{}
+
+// ContextExtractor extracts some attrs from context.
+// All attrs extracted will be added to the log.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
 // NewContext wraps context with logger and returns a new context.
 func NewContext(ctx context.Context, logger *Logger) context.Context {
 	return context.WithValue(ctx, contextKey{}, logger)
@@ -33,3 +40,30 @@ func FromContext(ctx context.Context) *Logger {
 
 	return Default()
 }
+
+// WithContextAttrs wraps context with args and returns a new context.
+// The args will be added to all logs logged with the new context, see Logger.InfoContext.
+// Args in the parent context will be kept and the new args will be appended to them.
+func WithContextAttrs(ctx context.Context, args ...any) context.Context {
+	attrs := newAttrs(args)
+	if len(attrs) <= 0 {
+		return ctx
+	}
+
+	parentAttrs := ContextAttrs(ctx)
+	if len(parentAttrs) > 0 {
+		attrs = append(parentAttrs[:len(parentAttrs):len(parentAttrs)], attrs...)
+	}
+
+	return context.WithValue(ctx, contextAttrsKey{}, attrs)
+}
+
+// ContextAttrs gets attrs from context and returns nil if missed.
+// See WithContextAttrs.
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	if attrs, ok := ctx.Value(contextAttrsKey{}).([]slog.Attr); ok {
+		return attrs
+	}
+
+	return nil
+}
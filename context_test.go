@@ -16,6 +16,7 @@ package logit
 
 import (
 	"context"
+	"log/slog"
 	"testing"
 )
 
@@ -55,3 +56,53 @@ func TestFromContext(t *testing.T) {
 		t.Fatalf("contextLogger %+v != logger %+v", contextLogger, logger)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithContextAttrs$
+func TestWithContextAttrs(t *testing.T) {
+	ctx := context.Background()
+
+	newCtx := WithContextAttrs(ctx)
+	if newCtx != ctx {
+		t.Fatalf("newCtx %+v != ctx %+v", newCtx, ctx)
+	}
+
+	ctx = WithContextAttrs(ctx, "key1", 1)
+	parentCtx := WithContextAttrs(ctx, slog.String("key2", "2"))
+	childCtx := WithContextAttrs(parentCtx, "key3", true)
+
+	attrs := ContextAttrs(parentCtx)
+	if len(attrs) != 2 {
+		t.Fatalf("len(attrs) %d != 2", len(attrs))
+	}
+
+	if attrs[0].String() != "key1=1" || attrs[1].String() != "key2=2" {
+		t.Fatalf("attrs %+v is wrong", attrs)
+	}
+
+	attrs = ContextAttrs(childCtx)
+	if len(attrs) != 3 {
+		t.Fatalf("len(attrs) %d != 3", len(attrs))
+	}
+
+	if attrs[2].String() != "key3=true" {
+		t.Fatalf("attrs[2] %s is wrong", attrs[2])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestContextAttrs$
+func TestContextAttrs(t *testing.T) {
+	ctx := context.Background()
+
+	attrs := ContextAttrs(ctx)
+	if attrs != nil {
+		t.Fatalf("attrs %+v != nil", attrs)
+	}
+
+	want := []slog.Attr{slog.Int("key", 1)}
+	ctx = context.WithValue(ctx, contextAttrsKey{}, want)
+
+	attrs = ContextAttrs(ctx)
+	if len(attrs) != len(want) || attrs[0].String() != want[0].String() {
+		t.Fatalf("attrs %+v != want %+v", attrs, want)
+	}
+}
@@ -15,6 +15,7 @@
 package logit
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"sync/atomic"
@@ -40,43 +41,117 @@ func Default() *Logger {
 
 // Debug logs a log with msg and args in debug level.
 func Debug(msg string, args ...any) {
-	Default().log(slog.LevelDebug, msg, args...)
+	Default().log(context.Background(), slog.LevelDebug, msg, args...)
 }
 
 // Info logs a log with msg and args in info level.
 func Info(msg string, args ...any) {
-	Default().log(slog.LevelInfo, msg, args...)
+	Default().log(context.Background(), slog.LevelInfo, msg, args...)
 }
 
 // Warn logs a log with msg and args in warn level.
 func Warn(msg string, args ...any) {
-	Default().log(slog.LevelWarn, msg, args...)
+	Default().log(context.Background(), slog.LevelWarn, msg, args...)
 }
 
 // Error logs a log with msg and args in error level.
 func Error(msg string, args ...any) {
-	Default().log(slog.LevelError, msg, args...)
+	Default().log(context.Background(), slog.LevelError, msg, args...)
+}
+
+// Debugf logs a log with format and args in debug level.
+// The msg is formatted only if debug level is enabled.
+func Debugf(format string, args ...any) {
+	Default().logf(context.Background(), slog.LevelDebug, fmt.Sprintf, format, args)
+}
+
+// Infof logs a log with format and args in info level.
+// The msg is formatted only if info level is enabled.
+func Infof(format string, args ...any) {
+	Default().logf(context.Background(), slog.LevelInfo, fmt.Sprintf, format, args)
+}
+
+// Warnf logs a log with format and args in warn level.
+// The msg is formatted only if warn level is enabled.
+func Warnf(format string, args ...any) {
+	Default().logf(context.Background(), slog.LevelWarn, fmt.Sprintf, format, args)
+}
+
+// Errorf logs a log with format and args in error level.
+// The msg is formatted only if error level is enabled.
+func Errorf(format string, args ...any) {
+	Default().logf(context.Background(), slog.LevelError, fmt.Sprintf, format, args)
+}
+
+// Panic logs a log with msg and args in panic level.
+// It syncs the default logger and then panics with msg.
+func Panic(msg string, args ...any) {
+	logger := Default()
+	logger.log(context.Background(), defaults.LevelPanic, msg, args...)
+	logger.syncBeforeExiting()
+	panic(msg)
+}
+
+// Fatal logs a log with msg and args in fatal level.
+// It syncs the default logger and then exits the process with code 1.
+// See defaults.Exit.
+func Fatal(msg string, args ...any) {
+	logger := Default()
+	logger.log(context.Background(), defaults.LevelFatal, msg, args...)
+	logger.syncBeforeExiting()
+	defaults.Exit(1)
+}
+
+// Recover recovers a panic and logs it with the default logger.
+// It must be called directly by defer like "defer logit.Recover(msg)", or it can't recover panics.
+// See Logger.Recover.
+func Recover(msg string, opts ...RecoverOption) {
+	if value := recover(); value != nil {
+		Default().recoverPanic(value, msg, opts)
+	}
+}
+
+// DebugContext logs a log with ctx, msg and args in debug level.
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, slog.LevelDebug, msg, args...)
+}
+
+// InfoContext logs a log with ctx, msg and args in info level.
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, slog.LevelInfo, msg, args...)
+}
+
+// WarnContext logs a log with ctx, msg and args in warn level.
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, slog.LevelWarn, msg, args...)
+}
+
+// ErrorContext logs a log with ctx, msg and args in error level.
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	Default().log(ctx, slog.LevelError, msg, args...)
+}
+
+// Log logs a log with ctx, msg and args in given level.
+func Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	Default().log(ctx, level, msg, args...)
 }
 
 // Printf logs a log with format and args in print level.
 // It a old-school way to log.
 func Printf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	Default().log(defaults.LevelPrint, msg)
+	Default().logf(context.Background(), defaults.LevelPrint, fmt.Sprintf, format, args)
 }
 
 // Print logs a log with args in print level.
 // It a old-school way to log.
 func Print(args ...interface{}) {
-	msg := fmt.Sprint(args...)
-	Default().log(defaults.LevelPrint, msg)
+	Default().logf(context.Background(), defaults.LevelPrint, sprint, "", args)
 }
 
 // Println logs a log with args in print level.
 // It a old-school way to log.
 func Println(args ...interface{}) {
-	msg := fmt.Sprintln(args...)
-	Default().log(defaults.LevelPrint, msg)
+	Default().logf(context.Background(), defaults.LevelPrint, sprintln, "", args)
 }
 
 // Sync syncs the default logger and returns an error if failed.
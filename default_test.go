@@ -16,10 +16,13 @@ package logit
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"log/slog"
+	"strings"
 	"testing"
 
+	"github.com/FishGoddess/logit/defaults"
 	"github.com/FishGoddess/logit/handler"
 )
 
@@ -90,6 +93,67 @@ func TestDefaultLogger(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDefaultLoggerContext$
+func TestDefaultLoggerContext(t *testing.T) {
+	handler := &testContextHandler{}
+	SetDefault(&Logger{handler: handler})
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+	DebugContext(ctx, "debug msg")
+	InfoContext(ctx, "info msg")
+	WarnContext(ctx, "warn msg")
+	ErrorContext(ctx, "error msg")
+	Log(ctx, slog.LevelInfo, "log msg")
+
+	if len(handler.handleValues) != 5 {
+		t.Fatalf("len(handler.handleValues) %d != 5", len(handler.handleValues))
+	}
+
+	for i, value := range handler.handleValues {
+		if value != "value" {
+			t.Fatalf("handler.handleValues[%d] %+v != value", i, value)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDefaultLoggerPanicAndFatal$
+func TestDefaultLoggerPanicAndFatal(t *testing.T) {
+	exit := defaults.Exit
+	defer func() {
+		defaults.Exit = exit
+	}()
+
+	exitCode := 0
+	defaults.Exit = func(code int) {
+		exitCode = code
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	SetDefault(NewLogger(WithWriter(buffer), WithBatch(16)))
+
+	Fatal("fatal msg")
+
+	if exitCode != 1 {
+		t.Fatalf("exitCode %d != 1", exitCode)
+	}
+
+	if !strings.Contains(buffer.String(), "FATAL ¦ fatal msg") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+
+	defer func() {
+		if r := recover(); r != "panic msg" {
+			t.Fatalf("r %+v != panic msg", r)
+		}
+
+		if !strings.Contains(buffer.String(), "PANIC ¦ panic msg") {
+			t.Fatalf("buffer %s is wrong", buffer.String())
+		}
+	}()
+
+	Panic("panic msg")
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestDefaultLoggerSync$
 func TestDefaultLoggerSync(t *testing.T) {
 	syncer := &testSyncer{
@@ -28,6 +28,11 @@ var (
 	// You can collect all errors and count them for reporting.
 	// Notice that this function is called synchronously, so don't do too many things in it.
 	HandleError = func(label string, err error) {}
+
+	// Exit exits the process with code.
+	// It's called by fatal logging functions after syncing the logger.
+	// You can replace it in tests to avoid exiting.
+	Exit = os.Exit
 )
 
 var (
@@ -37,6 +42,12 @@ var (
 
 	// LevelPrint is the level used for printing logs.
 	LevelPrint = slog.LevelInfo
+
+	// LevelPanic is the level used for panic logs.
+	LevelPanic = slog.LevelError + 8
+
+	// LevelFatal is the level used for fatal logs.
+	LevelFatal = slog.LevelError + 12
 )
 
 var (
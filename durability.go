@@ -0,0 +1,142 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/FishGoddess/logit/writer"
+)
+
+type durabilityMode int
+
+const (
+	durabilityOnSync durabilityMode = iota
+	durabilityNever
+	durabilityInterval
+	durabilityEveryRecord
+)
+
+// DurabilityPolicy decides when logs are synced to stable storage, like fsync of files.
+// Syncing makes logs survive power loss, but it's much slower than writing.
+type DurabilityPolicy struct {
+	mode     durabilityMode
+	interval time.Duration
+}
+
+// FsyncOnSync syncs logs to stable storage only when Logger.Sync is called, and it's the default policy.
+func FsyncOnSync() DurabilityPolicy {
+	return DurabilityPolicy{mode: durabilityOnSync}
+}
+
+// FsyncNever never syncs logs to stable storage, and Logger.Sync only flushes logs in buffers to files.
+// Logs may be lost in power loss, but they won't be lost if only the process exits after syncing.
+func FsyncNever() DurabilityPolicy {
+	return DurabilityPolicy{mode: durabilityNever}
+}
+
+// FsyncInterval syncs logs to stable storage in interval and when Logger.Sync is called.
+// Logs written in the last interval may be lost in power loss.
+func FsyncInterval(interval time.Duration) DurabilityPolicy {
+	if interval <= 0 {
+		return FsyncOnSync()
+	}
+
+	return DurabilityPolicy{mode: durabilityInterval, interval: interval}
+}
+
+// FsyncEveryRecord syncs logs to stable storage after handling every record, so no logs will be lost after logging returns.
+// It's the slowest policy and is useful for audit logs.
+func FsyncEveryRecord() DurabilityPolicy {
+	return DurabilityPolicy{mode: durabilityEveryRecord}
+}
+
+// fsync reports whether Logger.Sync should sync logs to stable storage.
+func (dp DurabilityPolicy) fsync() bool {
+	return dp.mode != durabilityNever
+}
+
+// chainSyncer syncs writers in chain from the outermost to the innermost.
+// Middlewares are flushed so their data reach the writers they wrap, and only the innermost writer is synced to stable storage.
+// Middlewares which aren't flushers are synced since Sync is the only way to push their data.
+type chainSyncer struct {
+	writers []io.Writer
+	fsync   bool
+}
+
+// notStdoutAndStderr reports whether w isn't stdout or stderr, which can't be synced to stable storage.
+func notStdoutAndStderr(w io.Writer) bool {
+	return w != os.Stdout && w != os.Stderr
+}
+
+func (cs chainSyncer) sync(w io.Writer, innermost bool) error {
+	if innermost && cs.fsync && notStdoutAndStderr(w) {
+		if syncer, ok := w.(Syncer); ok {
+			return syncer.Sync()
+		}
+	}
+
+	if flusher, ok := w.(writer.Flusher); ok {
+		return flusher.Flush()
+	}
+
+	if syncer, ok := w.(Syncer); ok && !innermost {
+		return syncer.Sync()
+	}
+
+	return nil
+}
+
+func (cs chainSyncer) Sync() error {
+	var errs []error
+	for i := len(cs.writers) - 1; i >= 0; i-- {
+		if err := cs.sync(cs.writers[i], i == 0); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// durableHandler syncs after handling every record, see FsyncEveryRecord.
+type durableHandler struct {
+	handler slog.Handler
+	syncer  Syncer
+}
+
+func (dh *durableHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return dh.handler.Enabled(ctx, level)
+}
+
+func (dh *durableHandler) Handle(ctx context.Context, record slog.Record) error {
+	if err := dh.handler.Handle(ctx, record); err != nil {
+		return err
+	}
+
+	return dh.syncer.Sync()
+}
+
+func (dh *durableHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &durableHandler{handler: dh.handler.WithAttrs(attrs), syncer: dh.syncer}
+}
+
+func (dh *durableHandler) WithGroup(name string) slog.Handler {
+	return &durableHandler{handler: dh.handler.WithGroup(name), syncer: dh.syncer}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDurableWriter records data written and events of flushing and syncing.
+type testDurableWriter struct {
+	name   string
+	buffer *bytes.Buffer
+	events *[]string
+	lock   *sync.Mutex
+}
+
+func (tdw *testDurableWriter) record(event string) {
+	tdw.lock.Lock()
+	defer tdw.lock.Unlock()
+
+	*tdw.events = append(*tdw.events, event+" "+tdw.name)
+}
+
+func (tdw *testDurableWriter) Write(p []byte) (n int, err error) {
+	tdw.lock.Lock()
+	defer tdw.lock.Unlock()
+
+	return tdw.buffer.Write(p)
+}
+
+func (tdw *testDurableWriter) Flush() error {
+	tdw.record("flush")
+	return nil
+}
+
+func (tdw *testDurableWriter) Sync() error {
+	tdw.record("sync")
+	return nil
+}
+
+func (tdw *testDurableWriter) Events() []string {
+	tdw.lock.Lock()
+	defer tdw.lock.Unlock()
+
+	return append([]string(nil), *tdw.events...)
+}
+
+// testSyncOnlyWriter is a writer which can be synced but can't be flushed.
+type testSyncOnlyWriter struct {
+	writer *testDurableWriter
+}
+
+func (tsow testSyncOnlyWriter) Write(p []byte) (n int, err error) {
+	return tsow.writer.Write(p)
+}
+
+func (tsow testSyncOnlyWriter) Sync() error {
+	return tsow.writer.Sync()
+}
+
+func newTestDurableWriters(names ...string) []*testDurableWriter {
+	events := new([]string)
+	lock := new(sync.Mutex)
+
+	writers := make([]*testDurableWriter, 0, len(names))
+	for _, name := range names {
+		writers = append(writers, &testDurableWriter{name: name, buffer: new(bytes.Buffer), events: events, lock: lock})
+	}
+
+	return writers
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFsyncInterval$
+func TestFsyncInterval(t *testing.T) {
+	policy := FsyncInterval(time.Second)
+	if policy.mode != durabilityInterval || policy.interval != time.Second {
+		t.Fatalf("policy %+v is wrong", policy)
+	}
+
+	if policy = FsyncInterval(0); policy != FsyncOnSync() {
+		t.Fatalf("policy %+v != FsyncOnSync()", policy)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestChainSyncer$
+func TestChainSyncer(t *testing.T) {
+	testCases := []struct {
+		fsync bool
+		want  []string
+	}{
+		{fsync: true, want: []string{"flush buffer", "sync middleware", "sync file"}},
+		{fsync: false, want: []string{"flush buffer", "sync middleware", "flush file"}},
+	}
+
+	for _, testCase := range testCases {
+		writers := newTestDurableWriters("file", "middleware", "buffer")
+		middleware := testSyncOnlyWriter{writer: writers[1]}
+
+		syncer := chainSyncer{writers: []io.Writer{writers[0], middleware, writers[2]}, fsync: testCase.fsync}
+		if err := syncer.Sync(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := writers[0].Events(); !reflect.DeepEqual(got, testCase.want) {
+			t.Fatalf("got %+v != want %+v", got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerDurability$
+func TestLoggerDurability(t *testing.T) {
+	testCases := []struct {
+		policy DurabilityPolicy
+		want   []string
+	}{
+		{policy: FsyncOnSync(), want: []string{"flush buffer", "sync file"}},
+		{policy: FsyncNever(), want: []string{"flush buffer", "flush file"}},
+		{policy: FsyncEveryRecord(), want: []string{"flush buffer", "sync file", "flush buffer", "sync file"}},
+	}
+
+	for _, testCase := range testCases {
+		writers := newTestDurableWriters("file", "buffer")
+
+		newWriter := func() (io.Writer, error) {
+			return writers[0], nil
+		}
+
+		middleware := func(io.Writer) io.Writer {
+			return writers[1]
+		}
+
+		conf := newDefaultConfig()
+		conf.newWriter = newWriter
+		conf.writerMiddlewares = append(conf.writerMiddlewares, middleware)
+		conf.durability = testCase.policy
+
+		handler, syncer, _, err := conf.newHandler(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logger := &Logger{handler: handler, syncer: syncer, lifecycle: newLifecycle()}
+		logger.Info("durability")
+
+		if err = logger.Sync(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := writers[0].Events(); !reflect.DeepEqual(got, testCase.want) {
+			t.Fatalf("got %+v != want %+v", got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerDurabilityInterval$
+func TestLoggerDurabilityInterval(t *testing.T) {
+	writers := newTestDurableWriters("file")
+
+	logger, err := NewLoggerGracefully(WithWriter(writers[0]), WithDurability(FsyncInterval(10*time.Millisecond)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer logger.Close()
+
+	time.Sleep(55 * time.Millisecond)
+
+	if events := writers[0].Events(); len(events) < 2 || events[0] != "sync file" {
+		t.Fatalf("events %+v are wrong", events)
+	}
+}
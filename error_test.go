@@ -0,0 +1,46 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestErr$
+func TestErr(t *testing.T) {
+	attr := Err(io.EOF)
+	if attr.Key != keyErr {
+		t.Fatalf("attr.Key %s != keyErr %s", attr.Key, keyErr)
+	}
+
+	if attr.Value.Any() != io.EOF {
+		t.Fatalf("attr.Value %+v != io.EOF", attr.Value)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+	logger.Error("read failed", Err(fmt.Errorf("read file: %w", io.EOF)))
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := "ERROR ¦ read failed ¦ err.msg=read file: EOF ¦ err.type=*fmt.wrapError ¦ err.causes.0.msg=EOF ¦ err.causes.0.type=*errors.errorString"
+
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't end with %s", got, want)
+	}
+}
@@ -15,16 +15,29 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/defaults"
+	"github.com/FishGoddess/logit/handler"
 	"github.com/FishGoddess/logit/rotate"
+	"github.com/FishGoddess/logit/writer"
 )
 
 type WriterConfig struct {
 	// Target is where the writer writes logs.
-	// Values: "stdout", "stderr", or a file path like "./logit.log".
+	// Values: "stdout", "stderr", a file path like "./logit.log", or a network address like "tcp://127.0.0.1:514".
+	// Network schemes are tcp, tcp4, tcp6, udp, udp4, udp6, unix, unixgram and tls, like "unix:///var/run/logit.sock".
+	// Scheme tls means tcp with tls.
 	Target string `json:"target" yaml:"target" toml:"target" bson:"target"`
 
 	// FileRotate is log file should split and backup when satisfy some conditions.
@@ -58,6 +71,67 @@ type WriterConfig struct {
 	// BatchSize is the size of a batch.
 	// Only available when mode is "batch".
 	BatchSize uint64 `json:"batch_size" yaml:"batch_size" toml:"batch_size" bson:"batch_size"`
+
+	// BatchMaxBytes is the max bytes of a batch.
+	// You can use common words like "512B" or "4KB".
+	// Only available when mode is "batch".
+	BatchMaxBytes string `json:"batch_max_bytes" yaml:"batch_max_bytes" toml:"batch_max_bytes" bson:"batch_max_bytes"`
+
+	// FlushInterval is the max time that logs stay in buffer or batch.
+	// You can use common words like "200ms" or "1s".
+	// Only available when mode is "buffer" or "batch".
+	FlushInterval string `json:"flush_interval" yaml:"flush_interval" toml:"flush_interval" bson:"flush_interval"`
+
+	// Async is logs should be written by a background goroutine.
+	// It's useful if the target is slow, like a disk with high latency or a pipe.
+	Async bool `json:"async" yaml:"async" toml:"async" bson:"async"`
+
+	// AsyncQueueSize is the max count of logs in queue.
+	// Only available when async is true.
+	AsyncQueueSize uint64 `json:"async_queue_size" yaml:"async_queue_size" toml:"async_queue_size" bson:"async_queue_size"`
+
+	// AsyncOverflow is what to do when the queue is full.
+	// Values: "block", "drop_newest", "drop_oldest", "drop_below_level".
+	// An empty string means "block".
+	// Only available when async is true.
+	AsyncOverflow string `json:"async_overflow" yaml:"async_overflow" toml:"async_overflow" bson:"async_overflow"`
+
+	// AsyncDropLevel is the level that logs below it will be dropped when the queue is full.
+	// Values: debug, info, warn, error, panic, fatal.
+	// An empty string means warn.
+	// Only available when async overflow is "drop_below_level".
+	AsyncDropLevel string `json:"async_drop_level" yaml:"async_drop_level" toml:"async_drop_level" bson:"async_drop_level"`
+
+	// AsyncDrainTimeout is the timeout of draining the queue when syncing or closing.
+	// You can use common words like "5s" or "1m".
+	// Only available when async is true.
+	AsyncDrainTimeout string `json:"async_drain_timeout" yaml:"async_drain_timeout" toml:"async_drain_timeout" bson:"async_drain_timeout"`
+
+	// NetworkWriteTimeout is the timeout of writing logs to network.
+	// You can use common words like "5s" or "500ms".
+	// Only available when target is a network address.
+	NetworkWriteTimeout string `json:"network_write_timeout" yaml:"network_write_timeout" toml:"network_write_timeout" bson:"network_write_timeout"`
+
+	// NetworkMaxPending is the max size of logs kept while disconnected, and the oldest logs will be dropped if exceeded.
+	// You can use common words like "1MB" or "512KB".
+	// Only available when target is a network address.
+	NetworkMaxPending string `json:"network_max_pending" yaml:"network_max_pending" toml:"network_max_pending" bson:"network_max_pending"`
+
+	// NetworkTLSServerName is the server name used to verify the certificate of server.
+	// An empty string means the host of target.
+	// Only available when target is a tls address.
+	NetworkTLSServerName string `json:"network_tls_server_name" yaml:"network_tls_server_name" toml:"network_tls_server_name" bson:"network_tls_server_name"`
+
+	// NetworkTLSCAFile is the path of a pem file which has certificates of CAs to verify the certificate of server.
+	// An empty string means the CAs of system.
+	// Only available when target is a tls address.
+	NetworkTLSCAFile string `json:"network_tls_ca_file" yaml:"network_tls_ca_file" toml:"network_tls_ca_file" bson:"network_tls_ca_file"`
+
+	// NetworkTLSCertFile and NetworkTLSKeyFile are the paths of pem files of client certificate and key.
+	// They should be set together if the server verifies clients.
+	// Only available when target is a tls address.
+	NetworkTLSCertFile string `json:"network_tls_cert_file" yaml:"network_tls_cert_file" toml:"network_tls_cert_file" bson:"network_tls_cert_file"`
+	NetworkTLSKeyFile  string `json:"network_tls_key_file" yaml:"network_tls_key_file" toml:"network_tls_key_file" bson:"network_tls_key_file"`
 }
 
 func (wc *WriterConfig) parseFileOptions() ([]rotate.Option, error) {
@@ -88,26 +162,203 @@ func (wc *WriterConfig) parseFileOptions() ([]rotate.Option, error) {
 	return opts, nil
 }
 
-func (wc *WriterConfig) appendTargetOptions(opts []logit.Option) ([]logit.Option, error) {
-	target := strings.ToLower(wc.Target)
+func (wc *WriterConfig) parseModeOptions() ([]writer.Option, error) {
+	opts := make([]writer.Option, 0, 2)
 
-	if target == "" {
-		return opts, nil
+	if wc.FlushInterval != "" {
+		flushInterval, err := parseTimeDuration(wc.FlushInterval)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithFlushInterval(flushInterval))
 	}
 
-	if target == "stdout" {
-		opts = append(opts, logit.WithStdout())
-		return opts, nil
+	if wc.BatchMaxBytes != "" {
+		maxBytes, err := parseByteSize(wc.BatchMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithMaxBatchBytes(maxBytes))
+	}
+
+	return opts, nil
+}
+
+func (wc *WriterConfig) parseAsyncOptions() ([]writer.AsyncOption, error) {
+	opts := make([]writer.AsyncOption, 0, 4)
+
+	if wc.AsyncQueueSize > 0 {
+		opts = append(opts, writer.WithQueueSize(wc.AsyncQueueSize))
+	}
+
+	switch strings.ToLower(wc.AsyncOverflow) {
+	case "", "block":
+		opts = append(opts, writer.WithOverflowPolicy(writer.OverflowBlock))
+	case "drop_newest":
+		opts = append(opts, writer.WithOverflowPolicy(writer.OverflowDropNewest))
+	case "drop_oldest":
+		opts = append(opts, writer.WithOverflowPolicy(writer.OverflowDropOldest))
+	case "drop_below_level":
+		opts = append(opts, writer.WithOverflowPolicy(writer.OverflowDropBelowLevel))
+	default:
+		return nil, fmt.Errorf("logit: async overflow %s unknown", wc.AsyncOverflow)
+	}
+
+	if wc.AsyncDropLevel != "" {
+		dropLevel, err := handler.ParseLevel(wc.AsyncDropLevel)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithDropLevel(dropLevel))
+	}
+
+	if wc.AsyncDrainTimeout != "" {
+		drainTimeout, err := parseTimeDuration(wc.AsyncDrainTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithDrainTimeout(drainTimeout))
+	}
+
+	return opts, nil
+}
+
+func (wc *WriterConfig) parseTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{ServerName: wc.NetworkTLSServerName}
+
+	if wc.NetworkTLSCAFile != "" {
+		pem, err := os.ReadFile(wc.NetworkTLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("logit: no certificates found in tls ca file %s", wc.NetworkTLSCAFile)
+		}
+
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if wc.NetworkTLSCertFile != "" || wc.NetworkTLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(wc.NetworkTLSCertFile, wc.NetworkTLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (wc *WriterConfig) parseNetworkOptions(scheme string) ([]writer.NetworkOption, error) {
+	opts := make([]writer.NetworkOption, 0, 4)
+
+	if scheme == "tls" {
+		tlsConfig, err := wc.parseTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithTLS(tlsConfig))
+	}
+
+	if wc.NetworkWriteTimeout != "" {
+		writeTimeout, err := parseTimeDuration(wc.NetworkWriteTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithWriteTimeout(writeTimeout))
+	}
+
+	if wc.NetworkMaxPending != "" {
+		maxPending, err := parseByteSize(wc.NetworkMaxPending)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, writer.WithMaxPendingBytes(maxPending))
+	}
+
+	return opts, nil
+}
+
+// network returns the network and address of target and the options of network writer.
+// It returns false if the target isn't a network address.
+func (wc *WriterConfig) network() (network string, address string, opts []writer.NetworkOption, ok bool, err error) {
+	scheme, address, ok := parseNetworkTarget(wc.Target)
+	if !ok {
+		return "", "", nil, false, nil
+	}
+
+	opts, err = wc.parseNetworkOptions(scheme)
+	if err != nil {
+		return "", "", nil, false, err
+	}
+
+	network = scheme
+	if scheme == "tls" {
+		network = "tcp"
+	}
+
+	return network, address, opts, true, nil
+}
+
+func (wc *WriterConfig) newWriter() (func() (io.Writer, error), error) {
+	target := strings.ToLower(wc.Target)
+
+	if target == "" || target == "stdout" {
+		newWriter := func() (io.Writer, error) {
+			return os.Stdout, nil
+		}
+
+		return newWriter, nil
 	}
 
 	if target == "stderr" {
-		opts = append(opts, logit.WithStderr())
-		return opts, nil
+		newWriter := func() (io.Writer, error) {
+			return os.Stderr, nil
+		}
+
+		return newWriter, nil
+	}
+
+	network, address, networkOpts, ok, err := wc.network()
+	if err != nil {
+		return nil, err
 	}
 
+	if ok {
+		newWriter := func() (io.Writer, error) {
+			nw, err := writer.Network(network, address, networkOpts...)
+			if err != nil {
+				return nil, err
+			}
+
+			return nw, nil
+		}
+
+		return newWriter, nil
+	}
+
+	file := wc.Target
 	if !wc.FileRotate {
-		opts = append(opts, logit.WithFile(wc.Target))
-		return opts, nil
+		newWriter := func() (io.Writer, error) {
+			dir := filepath.Dir(file)
+			if err := defaults.OpenFileDir(dir, defaults.FileDirMode); err != nil {
+				return nil, err
+			}
+
+			return defaults.OpenFile(file, defaults.FileMode)
+		}
+
+		return newWriter, nil
 	}
 
 	fileOpts, err := wc.parseFileOptions()
@@ -115,49 +366,252 @@ func (wc *WriterConfig) appendTargetOptions(opts []logit.Option) ([]logit.Option
 		return nil, err
 	}
 
-	opts = append(opts, logit.WithRotateFile(wc.Target, fileOpts...))
-	return opts, nil
+	newWriter := func() (io.Writer, error) {
+		return rotate.New(file, fileOpts...)
+	}
+
+	return newWriter, nil
 }
 
-func (wc *WriterConfig) appendModeOptions(opts []logit.Option) ([]logit.Option, error) {
+// modeWriters returns the buffer and batch middlewares of writer.
+func (wc *WriterConfig) modeWriters() ([]func(io.Writer) io.Writer, error) {
+	var modeWriters []func(io.Writer) io.Writer
+
+	modeOpts, err := wc.parseModeOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	if wc.BufferSize != "" {
 		bufferSize, err := parseByteSize(wc.BufferSize)
 		if err != nil {
 			return nil, err
 		}
 
-		opts = append(opts, logit.WithBuffer(bufferSize))
+		modeWriters = append(modeWriters, func(w io.Writer) io.Writer {
+			return writer.Buffer(w, bufferSize, modeOpts...)
+		})
 	}
 
 	if wc.BatchSize > 0 {
-		opts = append(opts, logit.WithBatch(wc.BatchSize))
+		batchSize := wc.BatchSize
+
+		modeWriters = append(modeWriters, func(w io.Writer) io.Writer {
+			return writer.Batch(w, batchSize, modeOpts...)
+		})
 	}
 
-	return opts, nil
+	return modeWriters, nil
+}
+
+// wrapWriters returns all middlewares of writer, and the async writer is the outermost one.
+func (wc *WriterConfig) wrapWriters() ([]func(io.Writer) io.Writer, error) {
+	wrapWriters, err := wc.modeWriters()
+	if err != nil {
+		return nil, err
+	}
+
+	if wc.Async {
+		asyncOpts, err := wc.parseAsyncOptions()
+		if err != nil {
+			return nil, err
+		}
+
+		wrapWriters = append(wrapWriters, func(w io.Writer) io.Writer {
+			return writer.Async(w, asyncOpts...)
+		})
+	}
+
+	return wrapWriters, nil
 }
 
 // Options parses a writer config and returns a list of options.
 // Return an error if parse failed.
 func (wc *WriterConfig) Options() (opts []logit.Option, err error) {
-	opts = make([]logit.Option, 0, 4)
+	opts = make([]logit.Option, 0, 3)
 
-	appendFuncs := []func(opts []logit.Option) ([]logit.Option, error){
-		wc.appendTargetOptions, wc.appendModeOptions,
+	if wc.Target != "" {
+		newWriter, err := wc.newWriter()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, logit.WithNewWriter(newWriter))
 	}
 
-	for _, append := range appendFuncs {
-		opts, err = append(opts)
+	modeWriters, err := wc.modeWriters()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(modeWriters) > 0 {
+		opts = append(opts, logit.WithWriterMiddleware(modeWriters...))
+	}
+
+	// Use async option so the async writer is still the outermost one if more middlewares are added.
+	if wc.Async {
+		asyncOpts, err := wc.parseAsyncOptions()
 		if err != nil {
 			return nil, err
 		}
+
+		opts = append(opts, logit.WithAsync(asyncOpts...))
+	}
+
+	return opts, nil
+}
+
+type OutputConfig struct {
+	// Level is the min level of records handled by this output.
+	// Values: debug, info, warn, error, panic, fatal.
+	// An empty string means debug.
+	Level string `json:"level" yaml:"level" toml:"level" bson:"level"`
+
+	// Handler is how the handler handles the logs.
+	// Values: "tape", "text", "json".
+	// An empty string means "tape".
+	Handler string `json:"handler" yaml:"handler" toml:"handler" bson:"handler"`
+
+	// Writer is the config of writer.
+	// An empty target means stdout.
+	Writer WriterConfig `json:"writer" yaml:"writer" toml:"writer" bson:"writer"`
+}
+
+// Output parses an output config and returns an output.
+// Return an error if parse failed.
+func (oc *OutputConfig) Output() (output logit.Output, err error) {
+	output.Level = slog.LevelDebug
+	if oc.Level != "" {
+		if output.Level, err = handler.ParseLevel(oc.Level); err != nil {
+			return output, err
+		}
+	}
+
+	output.Handler = strings.ToLower(oc.Handler)
+
+	if output.NewWriter, err = oc.Writer.newWriter(); err != nil {
+		return output, err
 	}
 
+	if output.Wrap, err = oc.Writer.wrapWriters(); err != nil {
+		return output, err
+	}
+
+	return output, nil
+}
+
+type SamplingConfig struct {
+	// Tick is the duration of one sampling period.
+	// An empty string means sampling is disabled.
+	// You can use common words like "1s" or "1m".
+	// See time.Duration and time.ParseDuration.
+	Tick string `json:"tick" yaml:"tick" toml:"tick" bson:"tick"`
+
+	// First is the count of records with the same level and message will be logged in one tick.
+	First int `json:"first" yaml:"first" toml:"first" bson:"first"`
+
+	// Thereafter means every thereafter record will be logged after the first records in one tick.
+	// Zero means all records after the first records will be dropped.
+	Thereafter int `json:"thereafter" yaml:"thereafter" toml:"thereafter" bson:"thereafter"`
+}
+
+// Options parses a sampling config and returns a list of options.
+// Return an error if parse failed.
+func (sc *SamplingConfig) Options() (opts []logit.Option, err error) {
+	if sc.Tick == "" {
+		return nil, nil
+	}
+
+	tick, err := parseTimeDuration(sc.Tick)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, logit.WithSampling(tick, sc.First, sc.Thereafter))
+	return opts, nil
+}
+
+type RedactionConfig struct {
+	// Keys are the sensitive keys whose values will be masked.
+	// They are case-insensitive and can be glob patterns like "*password*".
+	// See path.Match.
+	Keys []string `json:"keys" yaml:"keys" toml:"keys" bson:"keys"`
+
+	// Patterns are the patterns of sensitive values which will be masked.
+	// Values: "jwt", "credit_card", "bearer_token", or a regular expression.
+	Patterns []string `json:"patterns" yaml:"patterns" toml:"patterns" bson:"patterns"`
+
+	// Mask is the style of masking.
+	// Values: "full", "last4", "sha256".
+	// An empty string means "full".
+	Mask string `json:"mask" yaml:"mask" toml:"mask" bson:"mask"`
+}
+
+var (
+	redactionPatterns = map[string]*regexp.Regexp{
+		"jwt":          logit.PatternJWT,
+		"credit_card":  logit.PatternCreditCard,
+		"bearer_token": logit.PatternBearerToken,
+	}
+
+	redactionMasks = map[string]logit.RedactionMask{
+		"":       logit.MaskFull,
+		"full":   logit.MaskFull,
+		"last4":  logit.MaskKeepLast4,
+		"sha256": logit.MaskSHA256,
+	}
+)
+
+func (rc *RedactionConfig) parsePatterns() ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(rc.Patterns))
+	for _, pattern := range rc.Patterns {
+		if regex, ok := redactionPatterns[strings.ToLower(pattern)]; ok {
+			patterns = append(patterns, regex)
+			continue
+		}
+
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("logit: redaction pattern %s is invalid: %w", pattern, err)
+		}
+
+		patterns = append(patterns, regex)
+	}
+
+	return patterns, nil
+}
+
+// Options parses a redaction config and returns a list of options.
+// Return an error if parse failed.
+func (rc *RedactionConfig) Options() (opts []logit.Option, err error) {
+	if len(rc.Keys) <= 0 && len(rc.Patterns) <= 0 {
+		return nil, nil
+	}
+
+	for _, key := range rc.Keys {
+		if _, err = path.Match(strings.ToLower(key), ""); err != nil {
+			return nil, fmt.Errorf("logit: redaction key %s is invalid: %w", key, err)
+		}
+	}
+
+	patterns, err := rc.parsePatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	mask, ok := redactionMasks[strings.ToLower(rc.Mask)]
+	if !ok {
+		return nil, fmt.Errorf("logit: redaction mask %s unknown", rc.Mask)
+	}
+
+	opts = append(opts, logit.WithRedaction(rc.Keys, patterns, mask))
 	return opts, nil
 }
 
 type Config struct {
 	// Level is the level of logger.
-	// Values: debug, info, warn, error.
+	// Values: debug, info, warn, error, panic, fatal.
+	// Also, you can register your levels to logit, see handler.RegisterLevel.
 	Level string `json:"level" yaml:"level" toml:"level" bson:"level"`
 
 	// Handler is how the handler handles the logs.
@@ -168,6 +622,16 @@ type Config struct {
 	// Writer is the config of writer.
 	Writer WriterConfig `json:"writer" yaml:"writer" toml:"writer" bson:"writer"`
 
+	// Outputs are the configs of outputs, and every output has its own level, handler and writer.
+	// Handler and Writer will be ignored if outputs are set.
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs" toml:"outputs" bson:"outputs"`
+
+	// Sampling is the config of sampling.
+	Sampling SamplingConfig `json:"sampling" yaml:"sampling" toml:"sampling" bson:"sampling"`
+
+	// Redaction is the config of redaction.
+	Redaction RedactionConfig `json:"redaction" yaml:"redaction" toml:"redaction" bson:"redaction"`
+
 	// WithSource adds source to logs if true.
 	WithSource bool `json:"with_source" yaml:"with_source" toml:"with_source" bson:"with_source"`
 
@@ -179,6 +643,12 @@ type Config struct {
 	// You can use common words like "5m" or "60s".
 	// See time.Duration and time.ParseDuration.
 	SyncTimer string `json:"sync_timer" yaml:"sync_timer" toml:"sync_timer" bson:"sync_timer"`
+
+	// Durability is when logs are synced to stable storage.
+	// Values: "on_sync", "never", "every_record" or an interval like "100ms".
+	// An empty string means "on_sync".
+	// See logit.DurabilityPolicy.
+	Durability string `json:"durability" yaml:"durability" toml:"durability" bson:"durability"`
 }
 
 func (c *Config) appendLevelOptions(opts []logit.Option) ([]logit.Option, error) {
@@ -186,49 +656,72 @@ func (c *Config) appendLevelOptions(opts []logit.Option) ([]logit.Option, error)
 		return opts, nil
 	}
 
-	level := strings.ToLower(c.Level)
+	level, err := handler.ParseLevel(c.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, logit.WithLevel(level))
+	return opts, nil
+}
 
-	if level == "debug" {
-		opts = append(opts, logit.WithDebugLevel())
+func (c *Config) appendHandlerOptions(opts []logit.Option) ([]logit.Option, error) {
+	if c.Handler == "" {
 		return opts, nil
 	}
 
-	if level == "info" {
-		opts = append(opts, logit.WithInfoLevel())
-		return opts, nil
+	handlerName := strings.ToLower(c.Handler)
+	opts = append(opts, logit.WithHandler(handlerName))
+
+	return opts, nil
+}
+
+func (c *Config) appendWriterOptions(opts []logit.Option) ([]logit.Option, error) {
+	writerOpts, err := c.Writer.Options()
+	if err != nil {
+		return nil, err
 	}
 
-	if level == "warn" {
-		opts = append(opts, logit.WithWarnLevel())
+	opts = append(opts, writerOpts...)
+	return opts, nil
+}
+
+func (c *Config) appendOutputsOptions(opts []logit.Option) ([]logit.Option, error) {
+	if len(c.Outputs) <= 0 {
 		return opts, nil
 	}
 
-	if level == "error" {
-		opts = append(opts, logit.WithErrorLevel())
-		return opts, nil
+	outputs := make([]logit.Output, 0, len(c.Outputs))
+	for _, outputConfig := range c.Outputs {
+		output, err := outputConfig.Output()
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, output)
 	}
 
-	return nil, fmt.Errorf("logit: level %s unknown", level)
+	opts = append(opts, logit.WithOutputs(outputs...))
+	return opts, nil
 }
 
-func (c *Config) appendHandlerOptions(opts []logit.Option) ([]logit.Option, error) {
-	if c.Handler == "" {
-		return opts, nil
+func (c *Config) appendSamplingOptions(opts []logit.Option) ([]logit.Option, error) {
+	samplingOpts, err := c.Sampling.Options()
+	if err != nil {
+		return nil, err
 	}
 
-	handler := strings.ToLower(c.Handler)
-	opts = append(opts, logit.WithHandler(handler))
-
+	opts = append(opts, samplingOpts...)
 	return opts, nil
 }
 
-func (c *Config) appendWriterOptions(opts []logit.Option) ([]logit.Option, error) {
-	writerOpts, err := c.Writer.Options()
+func (c *Config) appendRedactionOptions(opts []logit.Option) ([]logit.Option, error) {
+	redactionOpts, err := c.Redaction.Options()
 	if err != nil {
 		return nil, err
 	}
 
-	opts = append(opts, writerOpts...)
+	opts = append(opts, redactionOpts...)
 	return opts, nil
 }
 
@@ -258,13 +751,37 @@ func (c *Config) appendSyncOptions(opts []logit.Option) ([]logit.Option, error)
 	return opts, nil
 }
 
+func (c *Config) appendDurabilityOptions(opts []logit.Option) ([]logit.Option, error) {
+	switch strings.ToLower(c.Durability) {
+	case "":
+		return opts, nil
+	case "on_sync":
+		opts = append(opts, logit.WithDurability(logit.FsyncOnSync()))
+	case "never":
+		opts = append(opts, logit.WithDurability(logit.FsyncNever()))
+	case "every_record":
+		opts = append(opts, logit.WithDurability(logit.FsyncEveryRecord()))
+	default:
+		interval, err := parseTimeDuration(c.Durability)
+		if err != nil {
+			return nil, fmt.Errorf("logit: durability %s unknown", c.Durability)
+		}
+
+		opts = append(opts, logit.WithDurability(logit.FsyncInterval(interval)))
+	}
+
+	return opts, nil
+}
+
 // Options parses a config and returns a list of options.
 // Return an error if parse failed.
 func (c *Config) Options() (opts []logit.Option, err error) {
 	opts = make([]logit.Option, 0, 4)
 
 	appendFuncs := []func(opts []logit.Option) ([]logit.Option, error){
-		c.appendLevelOptions, c.appendHandlerOptions, c.appendWriterOptions, c.appendFlagOptions, c.appendSyncOptions,
+		c.appendLevelOptions, c.appendHandlerOptions, c.appendWriterOptions, c.appendOutputsOptions,
+		c.appendSamplingOptions,
+		c.appendRedactionOptions, c.appendFlagOptions, c.appendSyncOptions, c.appendDurabilityOptions,
 	}
 
 	for _, append := range appendFuncs {
@@ -15,14 +15,26 @@
 package config
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"log/slog"
+	"math/big"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/FishGoddess/logit"
 	"github.com/FishGoddess/logit/defaults"
+	"github.com/FishGoddess/logit/handler"
+	"github.com/FishGoddess/logit/writer"
 )
 
 func removeTimeAndSource(str string) string {
@@ -112,3 +124,468 @@ func TestConfig(t *testing.T) {
 		t.Fatalf("got %s != want %s", got, want)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSamplingConfig$
+func TestSamplingConfig(t *testing.T) {
+	conf := SamplingConfig{}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts) != 0 {
+		t.Fatalf("len(opts) %d != 0", len(opts))
+	}
+
+	conf = SamplingConfig{Tick: "1s", First: 1, Thereafter: 0}
+
+	opts, err = conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts) != 1 {
+		t.Fatalf("len(opts) %d != 1", len(opts))
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	for i := 0; i < 10; i++ {
+		logger.Info("sampling")
+	}
+
+	if lines := strings.Count(buffer.String(), "sampling"); lines != 1 {
+		t.Fatalf("lines %d != 1", lines)
+	}
+
+	conf = SamplingConfig{Tick: "1x"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong tick should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigLevel$
+func TestConfigLevel(t *testing.T) {
+	levels := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+		"panic": defaults.LevelPanic,
+		"Fatal": defaults.LevelFatal,
+	}
+
+	for level, want := range levels {
+		conf := Config{Level: level, Writer: WriterConfig{Target: "stderr"}}
+
+		opts, err := conf.Options()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logger := logit.NewLogger(opts...)
+		if logger.Level() != want {
+			t.Fatalf("logger.Level() %+v != want %+v", logger.Level(), want)
+		}
+	}
+
+	if err := handler.RegisterLevel(slog.LevelDebug-4, "trace"); err != nil {
+		t.Fatal(err)
+	}
+
+	conf := Config{Level: "trace", Writer: WriterConfig{Target: "stderr"}}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	if logger.Level() != slog.LevelDebug-4 {
+		t.Fatalf("logger.Level() %+v != slog.LevelDebug-4", logger.Level())
+	}
+
+	conf = Config{Level: "unknown"}
+	if _, err := conf.Options(); err == nil {
+		t.Fatal("parse unknown level should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRedactionConfig$
+func TestRedactionConfig(t *testing.T) {
+	conf := RedactionConfig{}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts) != 0 {
+		t.Fatalf("len(opts) %d != 0", len(opts))
+	}
+
+	conf = RedactionConfig{Keys: []string{"*password*"}, Patterns: []string{"JWT", `id-\d+`}, Mask: "last4"}
+
+	opts, err = conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts) != 1 {
+		t.Fatalf("len(opts) %d != 1", len(opts))
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts = append(opts, logit.WithWriter(buffer))
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("redaction", "db_password", "123456", "user", "id-9527")
+
+	got := buffer.String()
+	if !strings.Contains(got, "db_password=******3456 ¦ user=******9527") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	conf = RedactionConfig{Keys: []string{"[password"}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong key should be failed")
+	}
+
+	conf = RedactionConfig{Patterns: []string{"(jwt"}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong pattern should be failed")
+	}
+
+	conf = RedactionConfig{Keys: []string{"password"}, Mask: "half"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong mask should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestOutputsConfig$
+func TestOutputsConfig(t *testing.T) {
+	infoFile := filepath.Join(t.TempDir(), t.Name()+"_info.log")
+	errorFile := filepath.Join(t.TempDir(), t.Name()+"_error.log")
+
+	conf := Config{
+		Level: "debug",
+		Outputs: []OutputConfig{
+			{Level: "info", Handler: "text", Writer: WriterConfig{Target: infoFile, BufferSize: "4KB"}},
+			{Level: "error", Handler: "json", Writer: WriterConfig{Target: errorFile, FileRotate: true, BatchSize: 16}},
+		},
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	logger.Debug("debug msg")
+	logger.Info("info msg")
+	logger.Error("error msg")
+	logger.Close()
+
+	infoBytes, err := os.ReadFile(infoFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.TrimSpace(removeTimeAndSource(string(infoBytes)))
+	want := `level=INFO msg="info msg" level=ERROR msg="error msg"`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	errorBytes, err := os.ReadFile(errorFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got = string(errorBytes)
+	if strings.Contains(got, "info msg") || !strings.Contains(got, `"msg":"error msg"`) {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	conf = Config{Outputs: []OutputConfig{{Level: "unknown"}}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong level should be failed")
+	}
+
+	conf = Config{Outputs: []OutputConfig{{Writer: WriterConfig{BufferSize: "4XB"}}}}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong buffer size should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterConfigAsync$
+func TestWriterConfigAsync(t *testing.T) {
+	conf := WriterConfig{
+		Async:             true,
+		AsyncQueueSize:    16,
+		AsyncOverflow:     "drop_below_level",
+		AsyncDropLevel:    "error",
+		AsyncDrainTimeout: "1s",
+	}
+
+	asyncOpts, err := conf.parseAsyncOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(asyncOpts) != 4 {
+		t.Fatalf("len(asyncOpts) %d != 4", len(asyncOpts))
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logFile := filepath.Join(t.TempDir(), t.Name()+".log")
+	opts = append(opts, logit.WithFile(logFile))
+
+	logger := logit.NewLogger(opts...)
+	logger.Info("async msg")
+	logger.Close()
+
+	gotBytes, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(gotBytes), "async msg") {
+		t.Fatalf("got %s doesn't contain async msg", gotBytes)
+	}
+
+	for _, overflow := range []string{"", "block", "drop_newest", "DROP_OLDEST"} {
+		conf = WriterConfig{Async: true, AsyncOverflow: overflow}
+		if _, err = conf.parseAsyncOptions(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conf = WriterConfig{Async: true, AsyncOverflow: "drop_all"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong overflow should be failed")
+	}
+
+	conf = WriterConfig{Async: true, AsyncDropLevel: "unknown"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong drop level should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterConfigMode$
+func TestWriterConfigMode(t *testing.T) {
+	conf := WriterConfig{BatchSize: 16, BatchMaxBytes: "4KB", FlushInterval: "200ms"}
+
+	modeOpts, err := conf.parseModeOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(modeOpts) != 2 {
+		t.Fatalf("len(modeOpts) %d != 2", len(modeOpts))
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(opts) != 1 {
+		t.Fatalf("len(opts) %d != 1", len(opts))
+	}
+
+	conf = WriterConfig{BufferSize: "4KB", FlushInterval: "1x"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong flush interval should be failed")
+	}
+
+	conf = WriterConfig{BatchSize: 16, BatchMaxBytes: "4XB"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong batch max bytes should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestConfigDurability$
+func TestConfigDurability(t *testing.T) {
+	durabilities := []string{"", "on_sync", "never", "every_record", "100ms", "1d"}
+
+	for _, durability := range durabilities {
+		conf := Config{Durability: durability}
+
+		opts, err := conf.appendDurabilityOptions(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := 1
+		if durability == "" {
+			want = 0
+		}
+
+		if len(opts) != want {
+			t.Fatalf("len(opts) %d != want %d", len(opts), want)
+		}
+	}
+
+	conf := Config{Durability: "always"}
+	if _, err := conf.appendDurabilityOptions(nil); err == nil {
+		t.Fatal("parse wrong durability should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterConfigNetwork$
+func TestWriterConfigNetwork(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	conf := WriterConfig{
+		Target:              "udp://" + conn.LocalAddr().String(),
+		NetworkWriteTimeout: "1s",
+		NetworkMaxPending:   "1MB",
+	}
+
+	networkOpts, err := conf.parseNetworkOptions("tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(networkOpts) != 3 {
+		t.Fatalf("len(networkOpts) %d != 3", len(networkOpts))
+	}
+
+	newWriter, err := conf.newWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := w.(*writer.NetworkWriter); !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	opts, err := conf.Options()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logit.NewLogger(opts...)
+	defer logger.Close()
+
+	logger.Info("network")
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	data := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data[:n]), "network") {
+		t.Fatalf("data %s is wrong", data[:n])
+	}
+
+	conf = WriterConfig{Target: "tcp://127.0.0.1:514", NetworkWriteTimeout: "1x"}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse wrong network write timeout should be failed")
+	}
+}
+
+// writeTestCert writes a self-signed certificate and its key to dir in pem.
+func writeTestCert(t *testing.T, dir string) (certFile string, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "logit"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, "cert.pem")
+	if err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile = filepath.Join(dir, "key.pem")
+	if err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWriterConfigTLS$
+func TestWriterConfigTLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	conf := WriterConfig{
+		Target:               "tls://127.0.0.1:6514",
+		NetworkTLSServerName: "logit",
+		NetworkTLSCAFile:     certFile,
+		NetworkTLSCertFile:   certFile,
+		NetworkTLSKeyFile:    keyFile,
+	}
+
+	tlsConfig, err := conf.parseTLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tlsConfig.ServerName != "logit" {
+		t.Fatalf("tlsConfig.ServerName %s != logit", tlsConfig.ServerName)
+	}
+
+	if tlsConfig.RootCAs == nil {
+		t.Fatal("tlsConfig.RootCAs == nil")
+	}
+
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("len(tlsConfig.Certificates) %d != 1", len(tlsConfig.Certificates))
+	}
+
+	if _, err = conf.Options(); err != nil {
+		t.Fatal(err)
+	}
+
+	conf = WriterConfig{Target: "tls://127.0.0.1:6514", NetworkTLSCAFile: keyFile}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse tls ca file without certificates should be failed")
+	}
+
+	conf = WriterConfig{Target: "tls://127.0.0.1:6514", NetworkTLSCertFile: certFile}
+	if _, err = conf.Options(); err == nil {
+		t.Fatal("parse tls cert file without key file should be failed")
+	}
+}
@@ -95,3 +95,20 @@ func parseTimeDuration(s string) (time.Duration, error) {
 
 	return time.ParseDuration(s)
 }
+
+// parseNetworkTarget parses network and address from a target like "tcp://127.0.0.1:514".
+// Schemes are tcp, tcp4, tcp6, udp, udp4, udp6, unix, unixgram and tls, and tls means tcp with tls.
+// It returns false if the target isn't a network target.
+func parseNetworkTarget(target string) (network string, address string, ok bool) {
+	scheme, address, ok := strings.Cut(target, "://")
+	if !ok {
+		return "", "", false
+	}
+
+	switch scheme = strings.ToLower(scheme); scheme {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram", "tls":
+		return scheme, address, true
+	default:
+		return "", "", false
+	}
+}
@@ -102,3 +102,31 @@ func TestParseTimeDuration(t *testing.T) {
 		})
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseNetworkTarget$
+func TestParseNetworkTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		wantNetwork string
+		wantAddress string
+		wantOK      bool
+	}{
+		{name: "tcp", target: "tcp://127.0.0.1:514", wantNetwork: "tcp", wantAddress: "127.0.0.1:514", wantOK: true},
+		{name: "UDP", target: "UDP://127.0.0.1:514", wantNetwork: "udp", wantAddress: "127.0.0.1:514", wantOK: true},
+		{name: "unix", target: "unix:///var/run/Logit.sock", wantNetwork: "unix", wantAddress: "/var/run/Logit.sock", wantOK: true},
+		{name: "tls", target: "tls://logs.example.com:6514", wantNetwork: "tls", wantAddress: "logs.example.com:6514", wantOK: true},
+		{name: "http", target: "http://127.0.0.1:80", wantNetwork: "", wantAddress: "", wantOK: false},
+		{name: "file", target: "./logit.log", wantNetwork: "", wantAddress: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, address, ok := parseNetworkTarget(tt.target)
+
+			if network != tt.wantNetwork || address != tt.wantAddress || ok != tt.wantOK {
+				t.Errorf("parseNetworkTarget() = %v, %v, %v, want %v, %v, %v", network, address, ok, tt.wantNetwork, tt.wantAddress, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	keyRepeated  = "repeated"
+	keyFirstTime = "first_time"
+	keyLastTime  = "last_time"
+)
+
+type dedupEntry struct {
+	handler slog.Handler
+	record  slog.Record
+
+	firstTime time.Time
+	lastTime  time.Time
+	repeated  int
+}
+
+// summary returns a record summarizing the repeated records.
+func (de *dedupEntry) summary() slog.Record {
+	record := de.record.Clone()
+	record.Time = de.lastTime
+	record.AddAttrs(
+		slog.Int(keyRepeated, de.repeated),
+		slog.Time(keyFirstTime, de.firstTime),
+		slog.Time(keyLastTime, de.lastTime),
+	)
+
+	return record
+}
+
+type dedupState struct {
+	entries   map[string]*dedupEntry
+	lastSweep time.Time
+
+	lock sync.Mutex
+}
+
+// sweep removes entries reached the deadline and returns the ones need to be summarized.
+func (ds *dedupState) sweep(deadline time.Time, all bool) (summaries []*dedupEntry) {
+	for key, entry := range ds.entries {
+		if !all && entry.firstTime.After(deadline) {
+			continue
+		}
+
+		if entry.repeated > 0 {
+			summaries = append(summaries, entry)
+		}
+
+		delete(ds.entries, key)
+	}
+
+	return summaries
+}
+
+// DedupHandler is a handler collapses identical records to reduce the amount of logs.
+// In a window, only the first one of identical records will be handled,
+// and a summary record carrying the repeated count and the first/last time will be handled later.
+// Records are identical if they have the same level, message and attrs.
+// The summaries are handled when the window passes and a record comes, or you call Sync.
+type DedupHandler struct {
+	handler slog.Handler
+	window  time.Duration
+
+	// prefix is the key prefix of attrs and groups added to this handler.
+	prefix string
+
+	state *dedupState
+}
+
+// Dedup returns a new dedup handler of handler with window.
+func Dedup(handler slog.Handler, window time.Duration) *DedupHandler {
+	if dh, ok := handler.(*DedupHandler); ok {
+		return dh
+	}
+
+	dh := &DedupHandler{
+		handler: handler,
+		window:  window,
+		state: &dedupState{
+			entries: make(map[string]*dedupEntry, 16),
+		},
+	}
+
+	return dh
+}
+
+// Enabled reports whether the handler should ignore logs whose level is lower than passed level.
+func (dh *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return dh.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with attrs.
+func (dh *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return dh
+	}
+
+	var prefix strings.Builder
+	prefix.WriteString(dh.prefix)
+
+	for _, attr := range attrs {
+		writeDedupAttr(&prefix, attr)
+		prefix.WriteByte(' ')
+	}
+
+	handler := *dh
+	handler.handler = dh.handler.WithAttrs(attrs)
+	handler.prefix = prefix.String()
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (dh *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return dh
+	}
+
+	handler := *dh
+	handler.handler = dh.handler.WithGroup(name)
+	handler.prefix = dh.prefix + name + groupConnector
+	return &handler
+}
+
+func (dh *DedupHandler) key(record slog.Record) string {
+	var key strings.Builder
+	key.WriteString(dh.prefix)
+	key.WriteString(strconv.Itoa(int(record.Level)))
+	key.WriteByte(' ')
+	key.WriteString(record.Message)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		key.WriteByte(' ')
+		writeDedupAttr(&key, attr)
+		return true
+	})
+
+	return key.String()
+}
+
+// writeDedupAttr writes attr to key with its resolved value.
+// Values which can't be compared like funcs and channels are written with their types only.
+func writeDedupAttr(key *strings.Builder, attr slog.Attr) {
+	value := attr.Value.Resolve()
+
+	key.WriteString(strconv.Quote(attr.Key))
+	key.WriteByte('=')
+
+	switch value.Kind() {
+	case slog.KindString:
+		key.WriteString(strconv.Quote(value.String()))
+	case slog.KindGroup:
+		key.WriteByte('{')
+
+		for i, groupAttr := range value.Group() {
+			if i > 0 {
+				key.WriteByte(' ')
+			}
+
+			writeDedupAttr(key, groupAttr)
+		}
+
+		key.WriteByte('}')
+	case slog.KindAny:
+		writeDedupAny(key, value.Any())
+	default:
+		key.WriteString(value.String())
+	}
+}
+
+func writeDedupAny(key *strings.Builder, v any) {
+	if err, ok := v.(error); ok {
+		key.WriteString(strconv.Quote(err.Error()))
+		return
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		key.WriteString(reflect.TypeOf(v).String())
+	default:
+		fmt.Fprintf(key, "%+v", v)
+	}
+}
+
+func (dh *DedupHandler) handleSummaries(summaries []*dedupEntry) error {
+	var errs []error
+	for _, entry := range summaries {
+		if err := entry.handler.Handle(context.Background(), entry.summary()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dedup reports whether the record is a duplicate one and returns the summaries need to be handled.
+func (dh *DedupHandler) dedup(record slog.Record) (bool, []*dedupEntry) {
+	now := record.Time
+	if now.IsZero() {
+		now = defaults.CurrentTime()
+	}
+
+	key := dh.key(record)
+	deadline := now.Add(-dh.window)
+
+	dh.state.lock.Lock()
+	defer dh.state.lock.Unlock()
+
+	// Sweep stale entries at most once in a window.
+	var summaries []*dedupEntry
+	if dh.state.lastSweep.Before(deadline) {
+		summaries = dh.state.sweep(deadline, false)
+		dh.state.lastSweep = now
+	}
+
+	if entry, ok := dh.state.entries[key]; ok {
+		if entry.firstTime.After(deadline) {
+			entry.lastTime = now
+			entry.repeated++
+			return true, summaries
+		}
+
+		if entry.repeated > 0 {
+			summaries = append(summaries, entry)
+		}
+	}
+
+	dh.state.entries[key] = &dedupEntry{
+		handler:   dh.handler,
+		record:    record.Clone(),
+		firstTime: now,
+		lastTime:  now,
+		repeated:  0,
+	}
+
+	return false, summaries
+}
+
+// Handle handles one record if it's not a duplicate one and returns an error if failed.
+func (dh *DedupHandler) Handle(ctx context.Context, record slog.Record) error {
+	duplicate, summaries := dh.dedup(record)
+	err := dh.handleSummaries(summaries)
+
+	if duplicate {
+		return err
+	}
+
+	return errors.Join(err, dh.handler.Handle(ctx, record))
+}
+
+// Sync handles all summaries of repeated records and returns an error if failed.
+func (dh *DedupHandler) Sync() error {
+	dh.state.lock.Lock()
+	summaries := dh.state.sweep(time.Time{}, true)
+	dh.state.lock.Unlock()
+
+	return dh.handleSummaries(summaries)
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDedup$
+func TestDedup(t *testing.T) {
+	handler := NewTapeHandler(bytes.NewBuffer(nil), nil)
+
+	dh := Dedup(handler, time.Second)
+	if dh.handler != handler {
+		t.Fatalf("dh.handler %+v != handler %+v", dh.handler, handler)
+	}
+
+	newHandler := Dedup(dh, time.Minute)
+	if newHandler != dh {
+		t.Fatalf("newHandler %+v != dh %+v", newHandler, dh)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDedupHandler$
+func TestDedupHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	dh := Dedup(NewTapeHandler(buffer, nil), time.Second)
+	childHandler := dh.WithAttrs([]slog.Attr{slog.Int("key", 1)}).WithGroup("group")
+
+	now := time.Now()
+	handle := func(handler slog.Handler, t time.Time, msg string, args ...any) {
+		record := slog.NewRecord(t, slog.LevelError, msg, 0)
+		record.Add(args...)
+
+		if err := handler.Handle(context.Background(), record); err != nil {
+			panic(err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		handle(dh, now.Add(time.Duration(i)*time.Millisecond), "db down", "err", "timeout")
+		handle(childHandler, now, "db down", "err", "timeout")
+	}
+
+	handle(dh, now, "db down", "err", "refused")
+
+	logs := buffer.String()
+	if lines := strings.Count(logs, "db down"); lines != 3 {
+		t.Fatalf("lines %d != 3", lines)
+	}
+
+	if strings.Contains(logs, keyRepeated) {
+		t.Fatalf("logs %s contains summary", logs)
+	}
+
+	// The window passes so summaries should be handled.
+	handle(dh, now.Add(2*time.Second), "db down", "err", "timeout")
+
+	logs = buffer.String()
+	if lines := strings.Count(logs, "db down"); lines != 6 {
+		t.Fatalf("lines %d != 6", lines)
+	}
+
+	if lines := strings.Count(logs, keyRepeated+"=9"); lines != 2 {
+		t.Fatalf("lines %d != 2", lines)
+	}
+
+	if lines := strings.Count(logs, "group.repeated=9"); lines != 1 {
+		t.Fatalf("lines %d != 1", lines)
+	}
+
+	if !strings.Contains(logs, keyFirstTime+"=") || !strings.Contains(logs, keyLastTime+"=") {
+		t.Fatalf("logs %s doesn't contain first and last time", logs)
+	}
+
+	// Sync handles all summaries.
+	handle(dh, now.Add(2*time.Second), "db down", "err", "timeout")
+
+	if err := dh.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	logs = buffer.String()
+	if lines := strings.Count(logs, keyRepeated+"=1"); lines != 1 {
+		t.Fatalf("lines %d != 1", lines)
+	}
+
+	if len(dh.state.entries) != 0 {
+		t.Fatalf("len(dh.state.entries) %d != 0", len(dh.state.entries))
+	}
+}
+
+type testDedupValuer struct {
+	value string
+}
+
+func (tdv *testDedupValuer) LogValue() slog.Value {
+	return slog.StringValue(tdv.value)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestDedupHandlerKey$
+func TestDedupHandlerKey(t *testing.T) {
+	dh := Dedup(NewTapeHandler(bytes.NewBuffer(nil), nil), time.Second)
+
+	key := func(args ...any) string {
+		record := slog.NewRecord(time.Time{}, slog.LevelInfo, "msg", 0)
+		record.Add(args...)
+
+		return dh.key(record)
+	}
+
+	testCases := []struct {
+		args  []any
+		args2 []any
+		same  bool
+	}{
+		{args: []any{"key", &testDedupValuer{value: "a"}}, args2: []any{"key", &testDedupValuer{value: "a"}}, same: true},
+		{args: []any{"key", &testDedupValuer{value: "a"}}, args2: []any{"key", &testDedupValuer{value: "b"}}, same: false},
+		{args: []any{"key", func() {}}, args2: []any{"key", func() {}}, same: true},
+		{args: []any{"key", make(chan int)}, args2: []any{"key", make(chan int)}, same: true},
+		{args: []any{"key", "a key2=b"}, args2: []any{"key", "a", "key2", "b"}, same: false},
+		{args: []any{slog.Group("group", "key", 1), "key", 2}, args2: []any{slog.Group("group", "key", 1, "key", 2)}, same: false},
+	}
+
+	for i, testCase := range testCases {
+		if same := key(testCase.args...) == key(testCase.args2...); same != testCase.same {
+			t.Fatalf("case %d: same %+v != testCase.same %+v", i, same, testCase.same)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+const (
+	keyErrorMsg    = "msg"
+	keyErrorType   = "type"
+	keyErrorValue  = "value"
+	keyErrorCauses = "causes"
+
+	// maxErrorDepth is the max depth of nested causes, so a weird error can't make rendering endless.
+	maxErrorDepth = 8
+
+	// maxErrorCauses is the max count of causes of one error.
+	maxErrorCauses = 32
+)
+
+// errorAttrs returns the attrs of err itself, including msg, type and attrs from slog.LogValuer.
+func errorAttrs(err error) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(keyErrorMsg, err.Error()),
+		slog.String(keyErrorType, fmt.Sprintf("%T", err)),
+	}
+
+	if _, ok := err.(slog.LogValuer); !ok {
+		return attrs
+	}
+
+	value := slog.AnyValue(err).Resolve()
+	if value.Kind() == slog.KindGroup {
+		return append(attrs, value.Group()...)
+	}
+
+	return append(attrs, slog.Attr{Key: keyErrorValue, Value: value})
+}
+
+// errorCauses returns the causes of err.
+// The chain from errors.Unwrap will be flattened, and each branch of errors.Join will be a cause with its own causes.
+// A joined error in chain is the last cause, and its branches are nested in its own causes so they won't look like the chain.
+func errorCauses(err error, depth int) []slog.Attr {
+	var causes []slog.Attr
+
+	appendCause := func(value slog.Value) {
+		causes = append(causes, slog.Attr{Key: strconv.Itoa(len(causes)), Value: value})
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, branch := range joined.Unwrap() {
+			if branch != nil && len(causes) < maxErrorCauses {
+				appendCause(errorValue(branch, depth+1))
+			}
+		}
+
+		return causes
+	}
+
+	for cause := errors.Unwrap(err); cause != nil && len(causes) < maxErrorCauses; cause = errors.Unwrap(cause) {
+		if _, ok := cause.(interface{ Unwrap() []error }); ok {
+			appendCause(errorValue(cause, depth+1))
+			break
+		}
+
+		appendCause(slog.GroupValue(errorAttrs(cause)...))
+	}
+
+	return causes
+}
+
+func errorValue(err error, depth int) slog.Value {
+	attrs := errorAttrs(err)
+
+	if depth < maxErrorDepth {
+		if causes := errorCauses(err, depth); len(causes) > 0 {
+			attrs = append(attrs, slog.Attr{Key: keyErrorCauses, Value: slog.GroupValue(causes...)})
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// ErrorValue returns a group value rendering err structurally.
+// The group has the message, the type, attrs from slog.LogValuer and the causes of err.
+// The chain from errors.Unwrap is flattened to causes like "causes.0" and "causes.1",
+// and the branches of errors.Join are nested in the causes of the joined error, like "causes.0.causes.1".
+func ErrorValue(err error) slog.Value {
+	if err == nil {
+		return slog.AnyValue(nil)
+	}
+
+	return errorValue(err, 0)
+}
+
+// replaceError returns a new options which replaces error values with structural values.
+// The replaceAttr in opts will be called before replacing error, so it still gets an error.
+func replaceError(opts *slog.HandlerOptions) *slog.HandlerOptions {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	replaceAttr := opts.ReplaceAttr
+
+	newOpts := *opts
+	newOpts.ReplaceAttr = func(groups []string, attr slog.Attr) slog.Attr {
+		if replaceAttr != nil {
+			attr = replaceAttr(groups, attr)
+		}
+
+		if attr.Value.Kind() == slog.KindAny {
+			if err, ok := attr.Value.Any().(error); ok {
+				attr.Value = ErrorValue(err)
+			}
+		}
+
+		return attr
+	}
+
+	return &newOpts
+}
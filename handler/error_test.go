@@ -0,0 +1,151 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type testValuerError struct {
+	code int
+}
+
+func (tve *testValuerError) Error() string {
+	return fmt.Sprintf("error with code %d", tve.code)
+}
+
+func (tve *testValuerError) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("code", tve.code))
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestErrorValue$
+func TestErrorValue(t *testing.T) {
+	if value := ErrorValue(nil); value.Any() != nil {
+		t.Fatalf("value %+v isn't nil", value)
+	}
+
+	valuerErr := &testValuerError{code: 500}
+	wrapped := fmt.Errorf("query failed: %w", fmt.Errorf("read failed: %w", io.EOF))
+	joined := errors.Join(wrapped, valuerErr)
+	err := fmt.Errorf("handle failed: %w", joined)
+
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{
+			err:  io.EOF,
+			want: `[msg=EOF type=*errors.errorString]`,
+		},
+		{
+			err:  valuerErr,
+			want: `[msg=error with code 500 type=*handler.testValuerError code=500]`,
+		},
+		{
+			err:  wrapped,
+			want: `[msg=query failed: read failed: EOF type=*fmt.wrapError causes=[0=[msg=read failed: EOF type=*fmt.wrapError] 1=[msg=EOF type=*errors.errorString]]]`,
+		},
+		{
+			err: err,
+			want: `[msg=handle failed: query failed: read failed: EOF
+error with code 500 type=*fmt.wrapError causes=[0=[msg=query failed: read failed: EOF
+error with code 500 type=*errors.joinError causes=[0=[msg=query failed: read failed: EOF type=*fmt.wrapError causes=[0=[msg=read failed: EOF type=*fmt.wrapError] 1=[msg=EOF type=*errors.errorString]]] 1=[msg=error with code 500 type=*handler.testValuerError code=500]]]]]`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if got := ErrorValue(testCase.err).String(); got != testCase.want {
+			t.Fatalf("got %s != want %s", got, testCase.want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestErrorValueDepth$
+func TestErrorValueDepth(t *testing.T) {
+	err := io.EOF
+	for i := 0; i < maxErrorDepth*2; i++ {
+		err = errors.Join(err)
+	}
+
+	got := ErrorValue(err).String()
+	if depth := strings.Count(got, keyErrorCauses); depth != maxErrorDepth {
+		t.Fatalf("depth %d != maxErrorDepth %d", depth, maxErrorDepth)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerError$
+func TestTapeHandlerError(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(NewTapeHandler(buffer, nil))
+
+	err := fmt.Errorf("read failed: %w", io.EOF)
+	logger.Info("msg", "err", err)
+
+	got := strings.Split(strings.TrimSpace(buffer.String()), string(attrConnector))
+	want := []string{
+		"err.msg=read failed: EOF", "err.type=*fmt.wrapError",
+		"err.causes.0.msg=EOF", "err.causes.0.type=*errors.errorString",
+	}
+
+	if strings.Join(got[3:], ",") != strings.Join(want, ",") {
+		t.Fatalf("got %+v != want %+v", got[3:], want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestJsonHandlerError$
+func TestJsonHandlerError(t *testing.T) {
+	newHandler, err := Get(Json)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(newHandler(buffer, nil))
+	logger.Info("msg", "err", errors.Join(io.EOF, &testValuerError{code: 404}))
+
+	var log struct {
+		Err struct {
+			Msg    string `json:"msg"`
+			Type   string `json:"type"`
+			Causes map[string]struct {
+				Msg  string `json:"msg"`
+				Code int    `json:"code"`
+			} `json:"causes"`
+		} `json:"err"`
+	}
+
+	if err = json.Unmarshal(buffer.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Err.Type != "*errors.joinError" {
+		t.Fatalf("log.Err.Type %s is wrong", log.Err.Type)
+	}
+
+	if log.Err.Causes["0"].Msg != "EOF" {
+		t.Fatalf("log.Err.Causes[0].Msg %s is wrong", log.Err.Causes["0"].Msg)
+	}
+
+	if log.Err.Causes["1"].Code != 404 {
+		t.Fatalf("log.Err.Causes[1].Code %d is wrong", log.Err.Causes["1"].Code)
+	}
+}
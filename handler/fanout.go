@@ -0,0 +1,98 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// FanoutHandler is a handler fans records out to several handlers.
+// A record will be handled by a handler only if the handler is enabled in the level of record.
+type FanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// Fanout returns a new fanout handler of handlers.
+func Fanout(handlers ...slog.Handler) *FanoutHandler {
+	fh := &FanoutHandler{
+		handlers: handlers,
+	}
+
+	return fh
+}
+
+// Handlers returns the handlers in fanout handler.
+func (fh *FanoutHandler) Handlers() []slog.Handler {
+	return fh.handlers
+}
+
+// Enabled reports whether one of handlers is enabled in level.
+func (fh *FanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range fh.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithAttrs returns a new handler with attrs.
+func (fh *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return fh
+	}
+
+	handlers := make([]slog.Handler, 0, len(fh.handlers))
+	for _, handler := range fh.handlers {
+		handlers = append(handlers, handler.WithAttrs(attrs))
+	}
+
+	return Fanout(handlers...)
+}
+
+// WithGroup returns a new handler with group.
+func (fh *FanoutHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return fh
+	}
+
+	handlers := make([]slog.Handler, 0, len(fh.handlers))
+	for _, handler := range fh.handlers {
+		handlers = append(handlers, handler.WithGroup(name))
+	}
+
+	return Fanout(handlers...)
+}
+
+// Handle handles one record by all handlers enabled in its level and returns an error if failed.
+// A failed handler won't stop other handlers from handling the record.
+func (fh *FanoutHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range fh.handlers {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		// Clone the record so handlers won't affect each other.
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
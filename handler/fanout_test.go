@@ -0,0 +1,93 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testFailedHandler struct {
+	slog.Handler
+}
+
+func (testFailedHandler) Handle(ctx context.Context, record slog.Record) error {
+	return errors.New("handle failed")
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFanoutHandler$
+func TestFanoutHandler(t *testing.T) {
+	debugBuffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	errorBuffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	fh := Fanout(
+		slog.NewTextHandler(debugBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewJSONHandler(errorBuffer, &slog.HandlerOptions{Level: slog.LevelError}),
+	)
+
+	if len(fh.Handlers()) != 2 {
+		t.Fatalf("len(fh.Handlers()) %d != 2", len(fh.Handlers()))
+	}
+
+	if !fh.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("fanout handler should be enabled in debug level")
+	}
+
+	logger := slog.New(fh).With("key", "value").WithGroup("group")
+	logger.Debug("debug msg", "k", 1)
+	logger.Error("error msg", "k", 2)
+
+	if got := strings.Count(debugBuffer.String(), "\n"); got != 2 {
+		t.Fatalf("got %d != 2", got)
+	}
+
+	if !strings.Contains(debugBuffer.String(), `msg="debug msg" key=value group.k=1`) {
+		t.Fatalf("debugBuffer %s is wrong", debugBuffer.String())
+	}
+
+	if got := strings.Count(errorBuffer.String(), "\n"); got != 1 {
+		t.Fatalf("got %d != 1", got)
+	}
+
+	if !strings.Contains(errorBuffer.String(), `"msg":"error msg","key":"value","group":{"k":2}`) {
+		t.Fatalf("errorBuffer %s is wrong", errorBuffer.String())
+	}
+
+	if Fanout().Enabled(context.Background(), slog.LevelError) {
+		t.Fatal("empty fanout handler should be disabled")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFanoutHandlerError$
+func TestFanoutHandlerError(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	textHandler := slog.NewTextHandler(buffer, nil)
+
+	fh := Fanout(testFailedHandler{textHandler}, textHandler)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	if err := fh.Handle(context.Background(), record); err == nil {
+		t.Fatal("handle should be failed")
+	}
+
+	if !strings.Contains(buffer.String(), "msg=msg") {
+		t.Fatalf("buffer %s is wrong", buffer.String())
+	}
+}
@@ -33,10 +33,10 @@ var (
 			return NewTapeHandler(w, opts)
 		},
 		Text: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewTextHandler(w, opts)
+			return slog.NewTextHandler(w, replaceLevel(opts))
 		},
 		Json: func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
-			return slog.NewJSONHandler(w, opts)
+			return slog.NewJSONHandler(w, replaceLevel(replaceError(opts)))
 		},
 	}
 )
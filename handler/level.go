@@ -0,0 +1,148 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// levelRegistry is the names of levels registered.
+// It's never modified after being stored, and registering copies it, so reading needs no locks.
+type levelRegistry struct {
+	levelNames map[slog.Level]string
+	nameLevels map[string]slog.Level
+}
+
+var (
+	levels atomic.Pointer[levelRegistry]
+
+	// registerLock serializes registering so no registered levels will be lost.
+	registerLock sync.Mutex
+)
+
+func init() {
+	registry := &levelRegistry{
+		levelNames: map[slog.Level]string{
+			slog.LevelDebug:     "DEBUG",
+			slog.LevelInfo:      "INFO",
+			slog.LevelWarn:      "WARN",
+			slog.LevelError:     "ERROR",
+			defaults.LevelPanic: "PANIC",
+			defaults.LevelFatal: "FATAL",
+		},
+		nameLevels: map[string]slog.Level{
+			"DEBUG": slog.LevelDebug,
+			"INFO":  slog.LevelInfo,
+			"WARN":  slog.LevelWarn,
+			"ERROR": slog.LevelError,
+			"PANIC": defaults.LevelPanic,
+			"FATAL": defaults.LevelFatal,
+		},
+	}
+
+	levels.Store(registry)
+}
+
+// LevelName returns the registered name of level.
+// The name will be level.String() if level isn't registered, like "DEBUG-4".
+func LevelName(level slog.Level) string {
+	if name, ok := levels.Load().levelNames[level]; ok {
+		return name
+	}
+
+	return level.String()
+}
+
+// ParseLevel parses name to level and returns an error if failed.
+// The name is case-insensitive and it can be a registered name or a name like "INFO+2".
+func ParseLevel(name string) (slog.Level, error) {
+	upperName := strings.ToUpper(strings.TrimSpace(name))
+
+	if level, ok := levels.Load().nameLevels[upperName]; ok {
+		return level, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(upperName)); err != nil {
+		return 0, fmt.Errorf("logit: level %s unknown", name)
+	}
+
+	return level, nil
+}
+
+// RegisterLevel registers level with name, so handlers will use the name to log.
+// The name is case-insensitive and it will be converted to upper case.
+func RegisterLevel(level slog.Level, name string) error {
+	upperName := strings.ToUpper(strings.TrimSpace(name))
+	if upperName == "" {
+		return fmt.Errorf("logit: level %d name is empty", level)
+	}
+
+	registerLock.Lock()
+	defer registerLock.Unlock()
+
+	registry := levels.Load()
+	if registeredName, registered := registry.levelNames[level]; registered {
+		return fmt.Errorf("logit: level %d has been registered as %s", level, registeredName)
+	}
+
+	if _, registered := registry.nameLevels[upperName]; registered {
+		return fmt.Errorf("logit: level name %s has been registered", upperName)
+	}
+
+	newRegistry := &levelRegistry{
+		levelNames: maps.Clone(registry.levelNames),
+		nameLevels: maps.Clone(registry.nameLevels),
+	}
+
+	newRegistry.levelNames[level] = upperName
+	newRegistry.nameLevels[upperName] = level
+	levels.Store(newRegistry)
+	return nil
+}
+
+// replaceLevel returns a new options which replaces the level attr with the registered level name.
+// The replaceAttr in opts will be called before replacing level, so it still gets a slog.Level.
+func replaceLevel(opts *slog.HandlerOptions) *slog.HandlerOptions {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	replaceAttr := opts.ReplaceAttr
+
+	newOpts := *opts
+	newOpts.ReplaceAttr = func(groups []string, attr slog.Attr) slog.Attr {
+		if replaceAttr != nil {
+			attr = replaceAttr(groups, attr)
+		}
+
+		if len(groups) <= 0 && attr.Key == slog.LevelKey {
+			if level, ok := attr.Value.Any().(slog.Level); ok {
+				attr.Value = slog.StringValue(LevelName(level))
+			}
+		}
+
+		return attr
+	}
+
+	return &newOpts
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLevelName$
+func TestLevelName(t *testing.T) {
+	levels := map[slog.Level]string{
+		slog.LevelDebug:     "DEBUG",
+		slog.LevelInfo:      "INFO",
+		slog.LevelWarn:      "WARN",
+		slog.LevelError:     "ERROR",
+		defaults.LevelPanic: "PANIC",
+		defaults.LevelFatal: "FATAL",
+		slog.LevelDebug - 7: "DEBUG-7",
+	}
+
+	for level, want := range levels {
+		if got := LevelName(level); got != want {
+			t.Fatalf("got %s != want %s", got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestParseLevel$
+func TestParseLevel(t *testing.T) {
+	levels := map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"Warn":    slog.LevelWarn,
+		" error ": slog.LevelError,
+		"panic":   defaults.LevelPanic,
+		"fatal":   defaults.LevelFatal,
+		"info+1":  slog.LevelInfo + 1,
+	}
+
+	for name, want := range levels {
+		got, err := ParseLevel(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != want {
+			t.Fatalf("name %s: got %+v != want %+v", name, got, want)
+		}
+	}
+
+	if _, err := ParseLevel("unknown"); err == nil {
+		t.Fatal("parse unknown level should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRegisterLevel$
+func TestRegisterLevel(t *testing.T) {
+	level := slog.Level(-7)
+	if err := RegisterLevel(level, ""); err == nil {
+		t.Fatal("register an empty name should be failed")
+	}
+
+	if err := RegisterLevel(slog.LevelInfo, "notice"); err == nil {
+		t.Fatal("register an existed level should be failed")
+	}
+
+	if err := RegisterLevel(level, "info"); err == nil {
+		t.Fatal("register an existed name should be failed")
+	}
+
+	if err := RegisterLevel(level, "Verbose"); err != nil {
+		t.Fatal(err)
+	}
+
+	if name := LevelName(level); name != "VERBOSE" {
+		t.Fatalf("name %s != VERBOSE", name)
+	}
+
+	got, err := ParseLevel("verbose")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != level {
+		t.Fatalf("got %+v != level %+v", got, level)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	opts := &slog.HandlerOptions{Level: level}
+
+	for _, name := range []string{Tape, Text, Json} {
+		newHandler, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buffer.Reset()
+		slog.New(newHandler(buffer, opts)).Log(context.Background(), level, "msg")
+
+		if !strings.Contains(buffer.String(), "VERBOSE") {
+			t.Fatalf("handler %s: buffer %s is wrong", name, buffer.String())
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestReplaceLevel$
+func TestReplaceLevel(t *testing.T) {
+	replaced := false
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if _, ok := attr.Value.Any().(slog.Level); ok {
+				replaced = true
+			}
+
+			return attr
+		},
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(slog.NewJSONHandler(buffer, replaceLevel(opts)))
+	logger.Log(context.Background(), defaults.LevelFatal, "msg")
+
+	if !replaced {
+		t.Fatal("replaceAttr in opts isn't called with a level")
+	}
+
+	var log map[string]any
+	if err := json.Unmarshal(buffer.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log[slog.LevelKey] != "FATAL" {
+		t.Fatalf("log[slog.LevelKey] %+v != FATAL", log[slog.LevelKey])
+	}
+
+	replaceLevel(nil)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRegisterLevelConcurrently$
+func TestRegisterLevelConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		level := slog.Level(-100 - i)
+		name := fmt.Sprintf("concurrent%d", i)
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			if err := RegisterLevel(level, name); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				LevelName(level)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// All levels should be registered even if they are registered concurrently.
+	for i := 0; i < 8; i++ {
+		if name := LevelName(slog.Level(-100 - i)); name != fmt.Sprintf("CONCURRENT%d", i) {
+			t.Fatalf("name %s is wrong", name)
+		}
+	}
+}
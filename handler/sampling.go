@@ -0,0 +1,185 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// samplingCounters is the number of counters used by sampling handler.
+	// Records are hashed to counters by level and message, so different records may share one counter.
+	samplingCounters = 4096
+)
+
+// samplingWindow is the count of records in one tick.
+type samplingWindow struct {
+	resetAt int64
+	count   atomic.Uint64
+}
+
+type samplingCounter struct {
+	window atomic.Pointer[samplingWindow]
+}
+
+// incr increases the counter and returns the count in current tick.
+// The counter will be reset if now reaches the reset time.
+// A new window is swapped in for resetting so the reset time and count always change together.
+func (sc *samplingCounter) incr(now int64, tick time.Duration) uint64 {
+	window := sc.window.Load()
+	if window != nil && now < window.resetAt {
+		return window.count.Add(1)
+	}
+
+	newWindow := &samplingWindow{resetAt: now + int64(tick)}
+	newWindow.count.Store(1)
+
+	if !sc.window.CompareAndSwap(window, newWindow) {
+		// Another goroutine has reset the counter.
+		return sc.window.Load().count.Add(1)
+	}
+
+	return 1
+}
+
+type samplingState struct {
+	counters [samplingCounters]samplingCounter
+
+	sampled atomic.Uint64
+	dropped atomic.Uint64
+}
+
+func (ss *samplingState) counter(level slog.Level, msg string) *samplingCounter {
+	hash := fnv.New32a()
+	hash.Write([]byte{byte(level)})
+	hash.Write([]byte(msg))
+
+	index := hash.Sum32() % samplingCounters
+	return &ss.counters[index]
+}
+
+// SamplingHandler is a handler samples records to reduce the amount of logs.
+// In every tick, it lets the first records with the same level and message through,
+// and then lets every thereafter record through, and drops others.
+// Handlers derived from it share the same counters, see WithAttrs and WithGroup.
+type SamplingHandler struct {
+	handler slog.Handler
+
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+
+	state *samplingState
+}
+
+// Sampling returns a new sampling handler of handler with tick, first and thereafter.
+// A thereafter less than or equal to 0 means dropping all records after the first ones in a tick.
+func Sampling(handler slog.Handler, tick time.Duration, first int, thereafter int) *SamplingHandler {
+	if sh, ok := handler.(*SamplingHandler); ok {
+		return sh
+	}
+
+	if first < 0 {
+		first = 0
+	}
+
+	if thereafter < 0 {
+		thereafter = 0
+	}
+
+	sh := &SamplingHandler{
+		handler:    handler,
+		tick:       tick,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		state:      new(samplingState),
+	}
+
+	return sh
+}
+
+// Sampled returns the count of records sampled.
+func (sh *SamplingHandler) Sampled() uint64 {
+	return sh.state.sampled.Load()
+}
+
+// Dropped returns the count of records dropped.
+func (sh *SamplingHandler) Dropped() uint64 {
+	return sh.state.dropped.Load()
+}
+
+// Enabled reports whether the handler should ignore logs whose level is lower than passed level.
+func (sh *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return sh.handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new handler with attrs.
+func (sh *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return sh
+	}
+
+	handler := *sh
+	handler.handler = sh.handler.WithAttrs(attrs)
+	return &handler
+}
+
+// WithGroup returns a new handler with group.
+func (sh *SamplingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return sh
+	}
+
+	handler := *sh
+	handler.handler = sh.handler.WithGroup(name)
+	return &handler
+}
+
+func (sh *SamplingHandler) sample(record slog.Record) bool {
+	now := record.Time
+	if now.IsZero() {
+		now = defaults.CurrentTime()
+	}
+
+	counter := sh.state.counter(record.Level, record.Message)
+	count := counter.incr(now.UnixNano(), sh.tick)
+
+	if count <= sh.first {
+		return true
+	}
+
+	if sh.thereafter <= 0 {
+		return false
+	}
+
+	return (count-sh.first)%sh.thereafter == 0
+}
+
+// Handle handles one record if it's sampled and returns an error if failed.
+func (sh *SamplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if !sh.sample(record) {
+		sh.state.dropped.Add(1)
+		return nil
+	}
+
+	sh.state.sampled.Add(1)
+	return sh.handler.Handle(ctx, record)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSamplingCounter$
+func TestSamplingCounter(t *testing.T) {
+	counter := new(samplingCounter)
+	now := time.Now().UnixNano()
+
+	for i := uint64(1); i <= 10; i++ {
+		if count := counter.incr(now, time.Second); count != i {
+			t.Fatalf("count %d != i %d", count, i)
+		}
+	}
+
+	now = now + int64(time.Second)
+	if count := counter.incr(now, time.Second); count != 1 {
+		t.Fatalf("count %d != 1", count)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSamplingCounterConcurrently$
+func TestSamplingCounterConcurrently(t *testing.T) {
+	counter := new(samplingCounter)
+	now := time.Now().UnixNano()
+
+	for i := 0; i < 100; i++ {
+		counter.incr(now, time.Second)
+	}
+
+	// All goroutines reach the reset time together, so each count in the new tick should be seen once.
+	now = now + int64(time.Second)
+	counts := make([]uint64, 64)
+
+	var wg sync.WaitGroup
+	for i := range counts {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			counts[i] = counter.incr(now, time.Second)
+		}(i)
+	}
+
+	wg.Wait()
+
+	seen := make(map[uint64]bool, len(counts))
+	for _, count := range counts {
+		if count < 1 || count > uint64(len(counts)) || seen[count] {
+			t.Fatalf("count %d is wrong in %+v", count, counts)
+		}
+
+		seen[count] = true
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSampling$
+func TestSampling(t *testing.T) {
+	handler := NewTapeHandler(bytes.NewBuffer(nil), nil)
+
+	sh := Sampling(handler, time.Second, -1, -1)
+	if sh.handler != handler {
+		t.Fatalf("sh.handler %+v != handler %+v", sh.handler, handler)
+	}
+
+	if sh.first != 0 || sh.thereafter != 0 {
+		t.Fatalf("sh.first %d or sh.thereafter %d is wrong", sh.first, sh.thereafter)
+	}
+
+	newHandler := Sampling(sh, time.Minute, 10, 10)
+	if newHandler != sh {
+		t.Fatalf("newHandler %+v != sh %+v", newHandler, sh)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestSamplingHandler$
+func TestSamplingHandler(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	sh := Sampling(NewTapeHandler(buffer, nil), time.Second, 3, 5)
+	logger := slog.New(sh)
+	childLogger := slog.New(sh.WithAttrs([]slog.Attr{slog.Int("key", 1)}).WithGroup("group"))
+
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		record := slog.NewRecord(now, slog.LevelInfo, "sampling", 0)
+		if err := logger.Handler().Handle(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 1, 2, 3, 8, 13, 18
+	if lines := strings.Count(buffer.String(), "sampling"); lines != 6 {
+		t.Fatalf("lines %d != 6", lines)
+	}
+
+	if sh.Sampled() != 6 {
+		t.Fatalf("sh.Sampled() %d != 6", sh.Sampled())
+	}
+
+	if sh.Dropped() != 14 {
+		t.Fatalf("sh.Dropped() %d != 14", sh.Dropped())
+	}
+
+	// Another message has its own counter.
+	childLogger.Info("another")
+	if lines := strings.Count(buffer.String(), "another"); lines != 1 {
+		t.Fatalf("lines %d != 1", lines)
+	}
+
+	// A new tick resets the counter.
+	record := slog.NewRecord(now.Add(time.Second), slog.LevelInfo, "sampling", 0)
+	if err := sh.Handle(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(buffer.String(), "sampling"); lines != 7 {
+		t.Fatalf("lines %d != 7", lines)
+	}
+
+	if sh.Sampled() != 8 {
+		t.Fatalf("sh.Sampled() %d != 8", sh.Sampled())
+	}
+
+	sh = Sampling(NewTapeHandler(buffer, nil), time.Second, 1, 0)
+	for i := 0; i < 10; i++ {
+		sh.Handle(context.Background(), slog.NewRecord(now, slog.LevelInfo, "no thereafter", 0))
+	}
+
+	if sh.Sampled() != 1 || sh.Dropped() != 9 {
+		t.Fatalf("sh.Sampled() %d or sh.Dropped() %d is wrong", sh.Sampled(), sh.Dropped())
+	}
+}
@@ -109,20 +109,12 @@ func (th *tapeHandler) WithGroup(name string) slog.Handler {
 	return &handler
 }
 
-func (th *tapeHandler) copyGroups(group string) []string {
-	cap := cap(th.groups)
-	if group != "" {
-		cap += 1
-	}
-
-	groups := make([]string, 0, cap)
-	groups = append(groups, th.groups...)
-
-	if group != "" {
-		groups = append(groups, group)
-	}
+func (th *tapeHandler) copyGroups(groups []string) []string {
+	newGroups := make([]string, 0, len(th.groups)+len(groups))
+	newGroups = append(newGroups, th.groups...)
+	newGroups = append(newGroups, groups...)
 
-	return groups
+	return newGroups
 }
 
 // Enabled reports whether the logger should ignore logs whose level is lower than passed level.
@@ -130,7 +122,7 @@ func (th *tapeHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return level >= th.opts.Level.Level()
 }
 
-func (th *tapeHandler) appendKey(bs []byte, group string, key string) []byte {
+func (th *tapeHandler) appendKey(bs []byte, groups []string, key string) []byte {
 	if key == "" {
 		return bs
 	}
@@ -140,7 +132,7 @@ func (th *tapeHandler) appendKey(bs []byte, group string, key string) []byte {
 		bs = append(bs, groupConnector...)
 	}
 
-	if group != "" {
+	for _, group := range groups {
 		bs = appendEscapedString(bs, group)
 		bs = append(bs, groupConnector...)
 	}
@@ -258,14 +250,8 @@ func (th *tapeHandler) appendTime(bs []byte, value time.Time) []byte {
 }
 
 func (th *tapeHandler) appendAny(bs []byte, value any) []byte {
-	if err, ok := value.(error); ok {
-		bs = append(bs, err.Error()...)
-		bs = append(bs, attrConnector...)
-		return bs
-	}
-
 	if stringer, ok := value.(fmt.Stringer); ok {
-		bs = append(bs, stringer.String()...)
+		bs = appendEscapedString(bs, stringer.String())
 		bs = append(bs, attrConnector...)
 		return bs
 	}
@@ -284,29 +270,39 @@ func (th *tapeHandler) appendAny(bs []byte, value any) []byte {
 	return bs
 }
 
-func (th *tapeHandler) appendAttr(bs []byte, group string, attr slog.Attr) []byte {
-	kind := attr.Value.Kind()
-	replaceAttr := th.opts.ReplaceAttr
+func (th *tapeHandler) appendAttr(bs []byte, groups []string, attr slog.Attr) []byte {
+	// Resolve the Attr's value before doing anything else.
+	attr.Value = attr.Value.Resolve()
 
-	if replaceAttr != nil && kind != slog.KindGroup {
-		groups := th.copyGroups(group)
+	replaceAttr := th.opts.ReplaceAttr
+	if replaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = replaceAttr(th.copyGroups(groups), attr)
 		attr.Value = attr.Value.Resolve()
-		attr = replaceAttr(groups, attr)
 	}
 
-	// Resolve the Attr's value before doing anything else.
-	attr.Value = attr.Value.Resolve()
-
 	if attr.Equal(emptyAttr) {
 		return bs
 	}
 
+	if attr.Value.Kind() == slog.KindAny {
+		if err, ok := attr.Value.Any().(error); ok {
+			attr.Value = ErrorValue(err)
+		}
+	}
+
+	// The kind may be changed by resolving and replacing, so get it at last.
+	kind := attr.Value.Kind()
+
 	if kind == slog.KindGroup {
-		bs = th.appendAttrs(bs, attr.Key, attr.Value.Group())
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+
+		bs = th.appendAttrs(bs, groups, attr.Value.Group())
 		return bs
 	}
 
-	bs = th.appendKey(bs, group, attr.Key)
+	bs = th.appendKey(bs, groups, attr.Key)
 
 	switch kind {
 	case slog.KindBool:
@@ -330,9 +326,9 @@ func (th *tapeHandler) appendAttr(bs []byte, group string, attr slog.Attr) []byt
 	return bs
 }
 
-func (th *tapeHandler) appendAttrs(bs []byte, group string, attrs []slog.Attr) []byte {
+func (th *tapeHandler) appendAttrs(bs []byte, groups []string, attrs []slog.Attr) []byte {
 	for _, attr := range attrs {
-		bs = th.appendAttr(bs, group, attr)
+		bs = th.appendAttr(bs, groups, attr)
 	}
 
 	return bs
@@ -355,6 +351,17 @@ func (th *tapeHandler) appendSource(bs []byte, pc uintptr) []byte {
 	return bs
 }
 
+// message returns msg replaced by opts.ReplaceAttr in a message attr like text and json handlers do.
+// So sensitive data in messages can be redacted too.
+func (th *tapeHandler) message(msg string) string {
+	if th.opts.ReplaceAttr == nil {
+		return msg
+	}
+
+	attr := th.opts.ReplaceAttr(nil, slog.String(slog.MessageKey, msg))
+	return attr.Value.Resolve().String()
+}
+
 // Handle handles one record and returns an error if failed.
 func (th *tapeHandler) Handle(ctx context.Context, record slog.Record) error {
 	// Setup a buffer for handling record.
@@ -368,14 +375,14 @@ func (th *tapeHandler) Handle(ctx context.Context, record slog.Record) error {
 
 	// Handling record.
 	bs = th.appendTime(bs, record.Time)
-	bs = th.appendString(bs, record.Level.String())
-	bs = th.appendString(bs, record.Message)
+	bs = th.appendString(bs, LevelName(record.Level))
+	bs = th.appendString(bs, th.message(record.Message))
 	bs = th.appendSource(bs, record.PC)
-	bs = th.appendAttrs(bs, "", th.attrs)
+	bs = th.appendAttrs(bs, nil, th.attrs)
 
 	if record.NumAttrs() > 0 {
 		record.Attrs(func(attr slog.Attr) bool {
-			bs = th.appendAttr(bs, "", attr)
+			bs = th.appendAttr(bs, nil, attr)
 			return true
 		})
 	}
@@ -16,6 +16,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -25,6 +26,8 @@ import (
 	"testing"
 	"testing/slogtest"
 	"time"
+
+	"github.com/FishGoddess/logit/defaults"
 )
 
 type demo struct {
@@ -197,3 +200,103 @@ func TestTapeHandler(t *testing.T) {
 		t.Log(err)
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerLevel$
+func TestTapeHandlerLevel(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	handler := NewTapeHandler(buffer, nil)
+
+	levels := map[slog.Level]string{
+		slog.LevelInfo:      "INFO",
+		slog.LevelError + 2: "ERROR+2",
+		defaults.LevelPanic: "PANIC",
+		defaults.LevelFatal: "FATAL",
+	}
+
+	for level, want := range levels {
+		buffer.Reset()
+
+		record := slog.NewRecord(time.Now(), level, "msg", 0)
+		if err := handler.Handle(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+
+		got := strings.Split(buffer.String(), string(attrConnector))[1]
+		if got != want {
+			t.Fatalf("got %s != want %s", got, want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerEscapeAny$
+func TestTapeHandlerEscapeAny(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(NewTapeHandler(buffer, nil))
+
+	logger.Info("msg", "err", errors.New("line1\nline2"), "demo", &demo{"a\tb"})
+
+	got := strings.Split(buffer.String(), string(attrConnector))
+	if got[3] != `err.msg=line1\nline2` {
+		t.Fatalf("got[3] %s is wrong", got[3])
+	}
+
+	if got[5] != `demo=a\tb`+string(lineBreak) {
+		t.Fatalf("got[5] %s is wrong", got[5])
+	}
+}
+
+type testGroupValuer struct{}
+
+func (testGroupValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("k1", 1), slog.Group("g2", "k2", 2))
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerNestedGroups$
+func TestTapeHandlerNestedGroups(t *testing.T) {
+	var replacedGroups []string
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == "k2" {
+			replacedGroups = groups
+			attr.Value = slog.StringValue("replaced")
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(NewTapeHandler(buffer, &slog.HandlerOptions{ReplaceAttr: replaceAttr}))
+	logger.WithGroup("g0").Info("msg", "g1", testGroupValuer{})
+
+	got := strings.Split(buffer.String(), string(attrConnector))
+	if got[3] != "g0.g1.k1=1" {
+		t.Fatalf("got[3] %s is wrong", got[3])
+	}
+
+	if got[4] != "g0.g1.g2.k2=replaced"+string(lineBreak) {
+		t.Fatalf("got[4] %s is wrong", got[4])
+	}
+
+	if strings.Join(replacedGroups, ",") != "g0,g1,g2" {
+		t.Fatalf("replacedGroups %+v is wrong", replacedGroups)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestTapeHandlerReplaceMessage$
+func TestTapeHandlerReplaceMessage(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.MessageKey {
+			attr.Value = slog.StringValue(strings.ReplaceAll(attr.Value.String(), "secret", "******"))
+		}
+
+		return attr
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := slog.New(NewTapeHandler(buffer, &slog.HandlerOptions{ReplaceAttr: replaceAttr}))
+	logger.Info("token secret")
+
+	got := strings.Split(buffer.String(), string(attrConnector))
+	if got[2] != "token ******"+string(lineBreak) {
+		t.Fatalf("got[2] %s is wrong", got[2])
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+type lazyValue func() any
+
+// LogValue returns the value computed by the function.
+func (lv lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(lv())
+}
+
+type lazyAttrs func() []slog.Attr
+
+// LogValue returns a group value of attrs computed by the function.
+func (la lazyAttrs) LogValue() slog.Value {
+	return slog.GroupValue(la()...)
+}
+
+// Lazy returns a log valuer which computes the value by fn only if the record is handled.
+// It's useful for expensive values, like serializing a request, and fn won't run if the level is disabled.
+// Notice that fn may run more than once if the record is handled by several handlers.
+func Lazy(fn func() any) slog.LogValuer {
+	return lazyValue(fn)
+}
+
+// LazyAttrs returns a log valuer which computes attrs by fn only if the record is handled.
+// The attrs will be a group named by the key, and they will be inlined if the key is empty.
+// Notice that fn may run more than once if the record is handled by several handlers.
+func LazyAttrs(fn func() []slog.Attr) slog.LogValuer {
+	return lazyAttrs(fn)
+}
+
+type formatFunc func(format string, args ...any) string
+
+func sprint(_ string, args ...any) string {
+	return fmt.Sprint(args...)
+}
+
+func sprintln(_ string, args ...any) string {
+	return fmt.Sprintln(args...)
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testStringer struct {
+	count int
+}
+
+func (ts *testStringer) String() string {
+	ts.count++
+	return "stringer"
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLazy$
+func TestLazy(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithInfoLevel())
+
+	count := 0
+	lazy := Lazy(func() any {
+		count++
+		return "value"
+	})
+
+	logger.Debug("debug msg", "key", lazy)
+	if count != 0 {
+		t.Fatalf("count %d != 0", count)
+	}
+
+	logger.Info("info msg", "key", lazy)
+	if count != 1 {
+		t.Fatalf("count %d != 1", count)
+	}
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	if !strings.HasSuffix(got, "INFO ¦ info msg ¦ key=value") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLazyAttrs$
+func TestLazyAttrs(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithInfoLevel(), WithTextHandler())
+
+	count := 0
+	lazy := LazyAttrs(func() []slog.Attr {
+		count++
+		return []slog.Attr{slog.Int("k1", 1), slog.String("k2", "v2")}
+	})
+
+	logger.Debug("debug msg", "group", lazy)
+	if count != 0 {
+		t.Fatalf("count %d != 0", count)
+	}
+
+	logger.Info("info msg", "group", lazy)
+	logger.Info("info msg", slog.Any("", lazy))
+
+	if count != 2 {
+		t.Fatalf("count %d != 2", count)
+	}
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := `level=INFO msg="info msg" group.k1=1 group.k2=v2 level=INFO msg="info msg" k1=1 k2=v2`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerFormatLazily$
+func TestLoggerFormatLazily(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithErrorLevel(), WithTextHandler())
+
+	defaultLogger := Default()
+	defer SetDefault(defaultLogger)
+
+	SetDefault(logger)
+
+	stringer := new(testStringer)
+	logger.Debugf("debugf %s", stringer)
+	logger.Infof("infof %s", stringer)
+	logger.Warnf("warnf %s", stringer)
+	logger.Printf("printf %s", stringer)
+	logger.Print("print ", stringer)
+	logger.Println("println", stringer)
+	Debugf("debugf %s", stringer)
+	Infof("infof %s", stringer)
+	Warnf("warnf %s", stringer)
+	Printf("printf %s", stringer)
+	Print("print ", stringer)
+	Println("println", stringer)
+
+	if stringer.count != 0 {
+		t.Fatalf("stringer.count %d != 0", stringer.count)
+	}
+
+	logger.Errorf("errorf %s", stringer)
+	Errorf("errorf %s", stringer)
+
+	if stringer.count != 2 {
+		t.Fatalf("stringer.count %d != 2", stringer.count)
+	}
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := `level=ERROR msg="errorf stringer" level=ERROR msg="errorf stringer"`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerFormatSource$
+func TestLoggerFormatSource(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler(), WithSource())
+
+	defaultLogger := Default()
+	defer SetDefault(defaultLogger)
+
+	SetDefault(logger)
+
+	lines := make([]int, 0, 4)
+
+	lines = append(lines, currentLine()+1)
+	logger.Infof("infof %d", 1)
+
+	lines = append(lines, currentLine()+1)
+	logger.Printf("printf %d", 1)
+
+	lines = append(lines, currentLine()+1)
+	Infof("infof %d", 1)
+
+	lines = append(lines, currentLine()+1)
+	Print("print")
+
+	sources := parseTestSources(t, buffer)
+	if len(sources) != len(lines) {
+		t.Fatalf("len(sources) %d != len(lines) %d", len(sources), len(lines))
+	}
+
+	for i, source := range sources {
+		if file := filepath.Base(source.Source.File); file != "lazy_test.go" {
+			t.Fatalf("file %s != lazy_test.go", file)
+		}
+
+		if source.Source.Line != lines[i] {
+			t.Fatalf("line %d != %d", source.Source.Line, lines[i])
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	// closeWaitTimeout is the max duration of waiting for records in handling when closing.
+	// Records may be blocked by writers which only stop blocking after closed, so we can't wait forever.
+	closeWaitTimeout = time.Second
+)
+
+var (
+	// ErrLoggerClosed is the error of logging to a closed logger.
+	ErrLoggerClosed = errors.New("logit: logger has been closed")
+)
+
+// lifecycle is the state of a logger shared with all loggers derived from it.
+// A nil lifecycle is valid and means the logger can't be closed more than once safely.
+type lifecycle struct {
+	closed atomic.Bool
+	done   chan struct{}
+
+	// handling is the count of records in handling so closing waits for them.
+	handling    atomic.Int64
+	waitTimeout time.Duration
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newLifecycle() *lifecycle {
+	lc := &lifecycle{
+		done:        make(chan struct{}),
+		waitTimeout: closeWaitTimeout,
+	}
+
+	return lc
+}
+
+// isClosed reports whether the logger has been closed.
+func (lc *lifecycle) isClosed() bool {
+	return lc != nil && lc.closed.Load()
+}
+
+// acquire marks a record in handling and returns false if the logger has been closed.
+// The caller should call release after handling if acquire returns true.
+func (lc *lifecycle) acquire() bool {
+	if lc == nil {
+		return true
+	}
+
+	// Mark handling before checking closed, so closing either sees the mark or this sees closed.
+	lc.handling.Add(1)
+
+	if lc.closed.Load() {
+		lc.handling.Add(-1)
+		return false
+	}
+
+	return true
+}
+
+// release marks a record acquired before finished handling.
+func (lc *lifecycle) release() {
+	if lc != nil {
+		lc.handling.Add(-1)
+	}
+}
+
+// waitHandling waits for records in handling until they are finished or timeout.
+func (lc *lifecycle) waitHandling() {
+	deadline := time.Now().Add(lc.waitTimeout)
+
+	for lc.handling.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// close marks the logger closed and calls fn only once.
+// It waits for records in handling before calling fn, so they won't be written to a closed handler.
+// The waiting has a timeout since records may be blocked by writers until they are closed by fn.
+// The first error returned by fn will be returned to all callers.
+func (lc *lifecycle) close(fn func() error) error {
+	if lc == nil {
+		return fn()
+	}
+
+	lc.closeOnce.Do(func() {
+		lc.closed.Store(true)
+		lc.waitHandling()
+
+		close(lc.done)
+
+		lc.closeErr = fn()
+	})
+
+	return lc.closeErr
+}
+
+func (l *Logger) runSyncTimer(d time.Duration) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := l.Sync(); err != nil {
+				defaults.HandleError("logit.Logger.Sync", err)
+			}
+		case <-l.lifecycle.done:
+			return
+		}
+	}
+}
+
+// shutdownSignals returns the signals to shut down the logger.
+// SIGINT and SIGTERM are used if no signals are given.
+func shutdownSignals(signals []os.Signal) []os.Signal {
+	if len(signals) > 0 {
+		return signals
+	}
+
+	return []os.Signal{os.Interrupt, syscall.SIGTERM}
+}
+
+// raiseSignal sends sig to the current process.
+var raiseSignal = func(sig os.Signal) error {
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+
+	return process.Signal(sig)
+}
+
+// runShutdownSignals waits for a signal from ch and then closes the logger and raises the signal again.
+// It returns directly if the logger is closed before receiving a signal.
+func (l *Logger) runShutdownSignals(ch chan os.Signal) {
+	defer signal.Stop(ch)
+
+	select {
+	case sig := <-ch:
+		if err := l.Close(); err != nil {
+			defaults.HandleError("logit.Logger.Close", err)
+		}
+
+		// Stop relaying signals to ch so the signal raised will be handled by its default action or other handlers.
+		signal.Stop(ch)
+
+		if err := raiseSignal(sig); err != nil {
+			defaults.HandleError("logit.raiseSignal", err)
+		}
+	case <-l.lifecycle.done:
+		return
+	}
+}
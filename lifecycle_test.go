@@ -0,0 +1,406 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+type countSyncer struct {
+	count atomic.Int64
+}
+
+func (cs *countSyncer) Sync() error {
+	cs.count.Add(1)
+	return nil
+}
+
+type countCloser struct {
+	count atomic.Int64
+}
+
+func (cc *countCloser) Close() error {
+	cc.count.Add(1)
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerCloseOnce$
+func TestLoggerCloseOnce(t *testing.T) {
+	syncer := new(countSyncer)
+	closer := new(countCloser)
+
+	logger := &Logger{
+		syncer:    syncer,
+		closer:    closer,
+		lifecycle: newLifecycle(),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := logger.clone().Close(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if count := syncer.count.Load(); count != 1 {
+		t.Fatalf("syncer.count %d != 1", count)
+	}
+
+	if count := closer.count.Load(); count != 1 {
+		t.Fatalf("closer.count %d != 1", count)
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := syncer.count.Load(); count != 1 {
+		t.Fatalf("syncer.count %d != 1", count)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerLogAfterClose$
+func TestLoggerLogAfterClose(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	var errs []error
+	defaults.HandleError = func(label string, err error) {
+		errs = append(errs, err)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer))
+	logger.Info("before close")
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := buffer.String()
+	logger.Info("after close")
+	logger.With("key", "value").Error("after close")
+	logger.Slog().Info("after close")
+
+	if got := buffer.String(); got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	if len(errs) != 3 {
+		t.Fatalf("len(errs) %d != 3", len(errs))
+	}
+
+	for _, err := range errs {
+		if !errors.Is(err, ErrLoggerClosed) {
+			t.Fatalf("err %+v isn't ErrLoggerClosed", err)
+		}
+	}
+}
+
+// blockingHandler blocks in handling until unblock is closed.
+type blockingHandler struct {
+	slog.Handler
+
+	handling chan struct{}
+	unblock  chan struct{}
+	closed   atomic.Bool
+	lost     atomic.Bool
+}
+
+func (bh *blockingHandler) Handle(ctx context.Context, record slog.Record) error {
+	close(bh.handling)
+	<-bh.unblock
+
+	if bh.closed.Load() {
+		bh.lost.Store(true)
+	}
+
+	return nil
+}
+
+func (bh *blockingHandler) Close() error {
+	bh.closed.Store(true)
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerCloseWhileHandling$
+func TestLoggerCloseWhileHandling(t *testing.T) {
+	handler := &blockingHandler{
+		Handler:  slog.NewTextHandler(io.Discard, nil),
+		handling: make(chan struct{}),
+		unblock:  make(chan struct{}),
+	}
+
+	logger := &Logger{
+		handler:   handler,
+		syncer:    new(countSyncer),
+		closer:    handler,
+		lifecycle: newLifecycle(),
+	}
+
+	logged := make(chan struct{})
+	go func() {
+		logger.Info("info msg")
+		close(logged)
+	}()
+
+	<-handler.handling
+
+	closed := make(chan struct{})
+	go func() {
+		logger.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("logger is closed before handling finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(handler.unblock)
+	<-logged
+	<-closed
+
+	if handler.lost.Load() {
+		t.Fatal("record is handled after closing")
+	}
+}
+
+// closeUnblockHandler blocks in handling until it's closed, like a writer blocked by a stuck sink.
+type closeUnblockHandler struct {
+	slog.Handler
+
+	handling chan struct{}
+	unblock  chan struct{}
+}
+
+func (cuh *closeUnblockHandler) Handle(ctx context.Context, record slog.Record) error {
+	close(cuh.handling)
+	<-cuh.unblock
+	return nil
+}
+
+func (cuh *closeUnblockHandler) Close() error {
+	close(cuh.unblock)
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerCloseWithStuckHandler$
+func TestLoggerCloseWithStuckHandler(t *testing.T) {
+	handler := &closeUnblockHandler{
+		Handler:  slog.NewTextHandler(io.Discard, nil),
+		handling: make(chan struct{}),
+		unblock:  make(chan struct{}),
+	}
+
+	logger := &Logger{
+		handler:   handler,
+		syncer:    new(countSyncer),
+		closer:    handler,
+		lifecycle: newLifecycle(),
+	}
+
+	logger.lifecycle.waitTimeout = 10 * time.Millisecond
+
+	logged := make(chan struct{})
+	go func() {
+		logger.Info("info msg")
+		close(logged)
+	}()
+
+	<-handler.handling
+
+	closed := make(chan struct{})
+	go func() {
+		logger.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("logger isn't closed after waiting timeout")
+	}
+
+	<-logged
+}
+
+// blockingSyncer blocks in syncing until unblock is closed.
+type blockingSyncer struct {
+	syncing chan struct{}
+	unblock chan struct{}
+}
+
+func (bs *blockingSyncer) Sync() error {
+	select {
+	case <-bs.syncing:
+	default:
+		close(bs.syncing)
+	}
+
+	<-bs.unblock
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSyncWhileClosing$
+func TestLoggerSyncWhileClosing(t *testing.T) {
+	syncer := &blockingSyncer{syncing: make(chan struct{}), unblock: make(chan struct{})}
+	closer := new(countCloser)
+
+	logger := &Logger{
+		syncer:    syncer,
+		closer:    closer,
+		lifecycle: newLifecycle(),
+	}
+
+	synced := make(chan struct{})
+	go func() {
+		logger.Sync()
+		close(synced)
+	}()
+
+	<-syncer.syncing
+
+	closed := make(chan struct{})
+	go func() {
+		logger.Close()
+		close(closed)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	if count := closer.count.Load(); count != 0 {
+		t.Fatalf("closer.count %d != 0", count)
+	}
+
+	close(syncer.unblock)
+	<-synced
+	<-closed
+
+	if count := closer.count.Load(); count != 1 {
+		t.Fatalf("closer.count %d != 1", count)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSyncTimer$
+func TestLoggerSyncTimer(t *testing.T) {
+	syncer := new(countSyncer)
+	closer := new(countCloser)
+
+	logger := &Logger{
+		syncer:    syncer,
+		closer:    closer,
+		lifecycle: newLifecycle(),
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		logger.runSyncTimer(10 * time.Millisecond)
+		close(stopped)
+	}()
+
+	time.Sleep(55 * time.Millisecond)
+
+	if count := syncer.count.Load(); count < 2 {
+		t.Fatalf("syncer.count %d < 2", count)
+	}
+
+	logger.Close()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("sync timer isn't stopped after closing")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerShutdownSignals$
+func TestLoggerShutdownSignals(t *testing.T) {
+	raise := raiseSignal
+	defer func() {
+		raiseSignal = raise
+	}()
+
+	var raised os.Signal
+	raiseSignal = func(sig os.Signal) error {
+		raised = sig
+		return nil
+	}
+
+	closer := new(countCloser)
+	logger := &Logger{
+		syncer:    new(countSyncer),
+		closer:    closer,
+		lifecycle: newLifecycle(),
+	}
+
+	ch := make(chan os.Signal, 1)
+	ch <- syscall.SIGTERM
+	logger.runShutdownSignals(ch)
+
+	if count := closer.count.Load(); count != 1 {
+		t.Fatalf("closer.count %d != 1", count)
+	}
+
+	if raised != syscall.SIGTERM {
+		t.Fatalf("raised %+v != syscall.SIGTERM", raised)
+	}
+
+	// Closing logger should stop waiting for signals.
+	logger = &Logger{
+		syncer:    new(countSyncer),
+		closer:    new(countCloser),
+		lifecycle: newLifecycle(),
+	}
+
+	logger.Close()
+	logger.runShutdownSignals(make(chan os.Signal, 1))
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestShutdownSignals$
+func TestShutdownSignals(t *testing.T) {
+	signals := shutdownSignals(nil)
+	if len(signals) != 2 || signals[0] != os.Interrupt || signals[1] != syscall.SIGTERM {
+		t.Fatalf("signals %+v is wrong", signals)
+	}
+
+	signals = shutdownSignals([]os.Signal{syscall.SIGHUP})
+	if len(signals) != 1 || signals[0] != syscall.SIGHUP {
+		t.Fatalf("signals %+v is wrong", signals)
+	}
+}
@@ -20,15 +20,18 @@ import (
 	"io"
 	"log/slog"
 	"os"
-	"runtime"
-	"time"
+	"os/signal"
+	"strings"
 
 	"github.com/FishGoddess/logit/defaults"
 )
 
 const (
-	keyBad = "!BADKEY"
-	keyPID = "pid"
+	keyBad    = "!BADKEY"
+	keyPID    = "pid"
+	keyLogger = "logger"
+
+	nameConnector = "."
 )
 
 var (
@@ -40,17 +43,38 @@ type Syncer interface {
 	Sync() error
 }
 
+// Hook is a function hooking a record before handling it.
+// You can inspect and mutate the record, like adding attrs or changing level.
+// Return false if you want to drop the record.
+type Hook func(ctx context.Context, record *slog.Record) bool
+
 // Logger is the entry of logging in logit.
 // It has several levels including debug, info, warn and error.
 // It's also a syncer or closer if handler is a syncer or closer.
 type Logger struct {
 	handler slog.Handler
+	level   *slog.LevelVar
+
+	name           string
+	nameLevel      slog.Leveler
+	levelOverrides map[string]slog.Level
 
 	syncer Syncer
 	closer io.Closer
 
+	contextExtractors []ContextExtractor
+	hooks             []Hook
+
 	withSource bool
 	withPID    bool
+
+	withStacktrace  bool
+	stacktraceLevel slog.Level
+
+	// callerSkip is the number of frames to skip besides defaults.CallerDepth.
+	callerSkip int
+
+	lifecycle *lifecycle
 }
 
 // NewLogger creates a logger with given options or panics if failed.
@@ -73,38 +97,42 @@ func NewLoggerGracefully(opts ...Option) (*Logger, error) {
 		opt.applyTo(conf)
 	}
 
-	handler, syncer, closer, err := conf.newHandler()
+	level := new(slog.LevelVar)
+	level.Set(conf.level)
+
+	handler, syncer, closer, err := conf.newHandler(level)
 	if err != nil {
 		return nil, err
 	}
 
 	logger := &Logger{
-		handler:    handler,
-		syncer:     syncer,
-		closer:     closer,
-		withSource: conf.withSource,
-		withPID:    conf.withPID,
+		handler:           handler,
+		level:             level,
+		levelOverrides:    conf.levelOverrides,
+		syncer:            syncer,
+		closer:            closer,
+		contextExtractors: conf.contextExtractors,
+		hooks:             conf.hooks,
+		withSource:        conf.withSource,
+		withPID:           conf.withPID,
+		withStacktrace:    conf.withStacktrace,
+		stacktraceLevel:   conf.stacktraceLevel,
+		lifecycle:         newLifecycle(),
 	}
 
-	if conf.syncTimer > 0 {
-		go logger.runSyncTimer(conf.syncTimer)
+	if interval := conf.syncInterval(); interval > 0 {
+		go logger.runSyncTimer(interval)
 	}
 
-	return logger, nil
-}
-
-func (l *Logger) runSyncTimer(d time.Duration) {
-	timer := time.NewTimer(d)
-	defer timer.Stop()
+	if conf.withShutdownSignals {
+		// Register signals before starting goroutine so no signals will be missed.
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, shutdownSignals(conf.shutdownSignals)...)
 
-	for {
-		select {
-		case <-timer.C:
-			if err := l.Sync(); err != nil {
-				defaults.HandleError("logit.Logger.Sync", err)
-			}
-		}
+		go logger.runShutdownSignals(ch)
 	}
+
+	return logger, nil
 }
 
 func (l *Logger) clone() *Logger {
@@ -113,7 +141,7 @@ func (l *Logger) clone() *Logger {
 	return &newLogger
 }
 
-func (l *Logger) squeezeAttr(args []any) (slog.Attr, []any) {
+func squeezeAttr(args []any) (slog.Attr, []any) {
 	// len of args must be > 0
 	switch arg := args[0].(type) {
 	case slog.Attr:
@@ -129,10 +157,10 @@ func (l *Logger) squeezeAttr(args []any) (slog.Attr, []any) {
 	}
 }
 
-func (l *Logger) newAttrs(args []any) (attrs []slog.Attr) {
+func newAttrs(args []any) (attrs []slog.Attr) {
 	var attr slog.Attr
 	for len(args) > 0 {
-		attr, args = l.squeezeAttr(args)
+		attr, args = squeezeAttr(args)
 		attrs = append(attrs, attr)
 	}
 
@@ -147,7 +175,7 @@ func (l *Logger) With(args ...any) *Logger {
 		return l
 	}
 
-	attrs := l.newAttrs(args)
+	attrs := newAttrs(args)
 	if len(attrs) <= 0 {
 		return l
 	}
@@ -171,124 +199,396 @@ func (l *Logger) WithGroup(name string) *Logger {
 
 }
 
+// matchLevelOverride finds the level override of name and returns false if missed.
+// The longest override matches the name in prefix will be used, so "db" matches "db" and "db.pool" but not "dbx".
+func matchLevelOverride(overrides map[string]slog.Level, name string) (level slog.Level, ok bool) {
+	matched := ""
+	for prefix, overrideLevel := range overrides {
+		if prefix == "" || len(prefix) <= len(matched) {
+			continue
+		}
+
+		if name == prefix || strings.HasPrefix(name, prefix+nameConnector) {
+			matched = prefix
+			level = overrideLevel
+			ok = true
+		}
+	}
+
+	return level, ok
+}
+
+// Named returns a new logger with name.
+// The name will be appended to the name of parent logger with a dot, like "db.pool".
+// All logs from the new logger will carry the name, and the level override of the name will be used if exists.
+// See WithLevelOverrides.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+
+	newLogger := l.clone()
+	if newLogger.name != "" {
+		newLogger.name = newLogger.name + nameConnector
+	}
+
+	newLogger.name = newLogger.name + name
+
+	if level, ok := matchLevelOverride(l.levelOverrides, newLogger.name); ok {
+		newLogger.nameLevel = level
+	}
+
+	return newLogger
+}
+
+// Name returns the name of logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// WithCallerSkip returns a new logger skipping n more frames when getting the source of logs.
+// It's useful for wrapper functions around a logger, and n is the number of wrapper frames.
+// A negative n reduces the skipped frames and the total will never be less than 0.
+// See Helper if you don't want to count the frames.
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	newLogger := l.clone()
+	newLogger.callerSkip += n
+
+	if newLogger.callerSkip < 0 {
+		newLogger.callerSkip = 0
+	}
+
+	return newLogger
+}
+
+// Handler returns the handler of logger.
+func (l *Logger) Handler() slog.Handler {
+	return l.handler
+}
+
+// Level returns the level of logger.
+func (l *Logger) Level() slog.Level {
+	return l.level.Level()
+}
+
+// SetLevel sets the level of logger at runtime.
+// The level is shared by the logger and all loggers derived from it, see With and WithGroup.
+// It only works for handlers which use slog.HandlerOptions.Level to check levels.
+func (l *Logger) SetLevel(level slog.Level) {
+	l.level.Set(level)
+}
+
 // enabled reports whether the logger should ignore logs whose level is lower.
-func (l *Logger) enabled(level slog.Level) bool {
-	return l.handler.Enabled(context.Background(), level)
+func (l *Logger) enabled(ctx context.Context, level slog.Level) bool {
+	// The level override of name replaces the level of logger.
+	// Outputs have their own levels, so the level of logger should be checked first.
+	if l.nameLevel != nil {
+		if level < l.nameLevel.Level() {
+			return false
+		}
+	} else if l.level != nil && level < l.level.Level() {
+		return false
+	}
+
+	// The handler may check the level of logger too, which shouldn't drop logs below it if the level override of name allows.
+	// Outputs still check their own levels in handling so asking with the higher level is fine.
+	if l.nameLevel != nil && l.level != nil {
+		return l.handler.Enabled(ctx, max(level, l.level.Level()))
+	}
+
+	return l.handler.Enabled(ctx, level)
 }
 
 // DebugEnabled reports whether the logger should ignore logs whose level is lower than debug.
 func (l *Logger) DebugEnabled() bool {
-	return l.enabled(slog.LevelDebug)
+	return l.enabled(context.Background(), slog.LevelDebug)
 }
 
 // InfoEnabled reports whether the logger should ignore logs whose level is lower than info.
 func (l *Logger) InfoEnabled() bool {
-	return l.enabled(slog.LevelInfo)
+	return l.enabled(context.Background(), slog.LevelInfo)
 }
 
 // WarnEnabled reports whether the logger should ignore logs whose level is lower than warn.
 func (l *Logger) WarnEnabled() bool {
-	return l.enabled(slog.LevelWarn)
+	return l.enabled(context.Background(), slog.LevelWarn)
 }
 
 // ErrorEnabled reports whether the logger should ignore logs whose level is lower than error.
 func (l *Logger) ErrorEnabled() bool {
-	return l.enabled(slog.LevelError)
+	return l.enabled(context.Background(), slog.LevelError)
 }
 
 // PrintEnabled reports whether the logger should ignore logs whose level is lower than print.
 func (l *Logger) PrintEnabled() bool {
-	return l.enabled(defaults.LevelPrint)
+	return l.enabled(context.Background(), defaults.LevelPrint)
 }
 
-func (l *Logger) newRecord(level slog.Level, msg string, args []any) slog.Record {
-	var pc uintptr
-
-	if l.withSource {
-		var pcs [1]uintptr
-		runtime.Callers(defaults.CallerDepth, pcs[:])
-		pc = pcs[0]
+func (l *Logger) addContextAttrs(ctx context.Context, record *slog.Record) {
+	if attrs := ContextAttrs(ctx); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
 	}
 
-	now := defaults.CurrentTime()
-	record := slog.NewRecord(now, level, msg, pc)
+	for _, extractor := range l.contextExtractors {
+		if attrs := extractor(ctx); len(attrs) > 0 {
+			record.AddAttrs(attrs...)
+		}
+	}
+}
 
+// addLoggerAttrs adds attrs of logger to record, including pid, name and attrs from context.
+func (l *Logger) addLoggerAttrs(ctx context.Context, record *slog.Record) {
 	if l.withPID {
 		record.AddAttrs(slog.Int(keyPID, pid))
 	}
 
+	if l.name != "" {
+		record.AddAttrs(slog.String(keyLogger, l.name))
+	}
+
+	l.addContextAttrs(ctx, record)
+}
+
+func (l *Logger) newRecord(ctx context.Context, level slog.Level, msg string, args []any) slog.Record {
+	withStack := l.withStacktrace && level >= l.stacktraceLevel
+	depth := defaults.CallerDepth + l.callerSkip
+
+	var pc uintptr
+	if l.withSource || withStack {
+		pc = callerPC(depth)
+	}
+
+	var sourcePC uintptr
+	if l.withSource {
+		sourcePC = pc
+	}
+
+	now := defaults.CurrentTime()
+	record := slog.NewRecord(now, level, msg, sourcePC)
+	l.addLoggerAttrs(ctx, &record)
+
 	var attr slog.Attr
 	for len(args) > 0 {
-		attr, args = l.squeezeAttr(args)
+		attr, args = squeezeAttr(args)
 		record.AddAttrs(attr)
 	}
 
+	if withStack {
+		record.AddAttrs(slog.Any(keyStack, captureStack(depth).from(pc)))
+	}
+
 	return record
 }
 
-func (l *Logger) log(level slog.Level, msg string, args ...any) {
-	if !l.enabled(level) {
+// runHooks runs all hooks on record and returns false if the record should be dropped.
+func (l *Logger) runHooks(ctx context.Context, record *slog.Record) bool {
+	if len(l.hooks) <= 0 {
+		return true
+	}
+
+	level := record.Level
+	for _, hook := range l.hooks {
+		if !hook(ctx, record) {
+			return false
+		}
+	}
+
+	// The level may be changed by hooks, so we check it again.
+	if record.Level != level {
+		return l.enabled(ctx, record.Level)
+	}
+
+	return true
+}
+
+// handle runs hooks on record and handles it if it isn't dropped by hooks.
+func (l *Logger) handle(ctx context.Context, record slog.Record) error {
+	if !l.lifecycle.acquire() {
+		return ErrLoggerClosed
+	}
+
+	defer l.lifecycle.release()
+
+	if !l.runHooks(ctx, &record) {
+		return nil
+	}
+
+	return l.handler.Handle(ctx, record)
+}
+
+func (l *Logger) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !l.enabled(ctx, level) {
+		return
+	}
+
+	record := l.newRecord(ctx, level, msg, args)
+
+	if err := l.handle(ctx, record); err != nil {
+		defaults.HandleError("Logger.handler.Handle", err)
+	}
+}
+
+// logf is like log but formats the msg only if the level is enabled.
+func (l *Logger) logf(ctx context.Context, level slog.Level, sprintf formatFunc, format string, args []any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !l.enabled(ctx, level) {
 		return
 	}
 
-	record := l.newRecord(level, msg, args)
+	msg := sprintf(format, args...)
+	record := l.newRecord(ctx, level, msg, nil)
 
-	if err := l.handler.Handle(context.Background(), record); err != nil {
+	if err := l.handle(ctx, record); err != nil {
 		defaults.HandleError("Logger.handler.Handle", err)
 	}
 }
 
 // Debug logs a log with msg and args in debug level.
 func (l *Logger) Debug(msg string, args ...any) {
-	l.log(slog.LevelDebug, msg, args...)
+	l.log(context.Background(), slog.LevelDebug, msg, args...)
 }
 
 // Info logs a log with msg and args in info level.
 func (l *Logger) Info(msg string, args ...any) {
-	l.log(slog.LevelInfo, msg, args...)
+	l.log(context.Background(), slog.LevelInfo, msg, args...)
 }
 
 // Warn logs a log with msg and args in warn level.
 func (l *Logger) Warn(msg string, args ...any) {
-	l.log(slog.LevelWarn, msg, args...)
+	l.log(context.Background(), slog.LevelWarn, msg, args...)
 }
 
 // Error logs a log with msg and args in error level.
 func (l *Logger) Error(msg string, args ...any) {
-	l.log(slog.LevelError, msg, args...)
+	l.log(context.Background(), slog.LevelError, msg, args...)
+}
+
+// Debugf logs a log with format and args in debug level.
+// The msg is formatted only if debug level is enabled.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelDebug, fmt.Sprintf, format, args)
+}
+
+// Infof logs a log with format and args in info level.
+// The msg is formatted only if info level is enabled.
+func (l *Logger) Infof(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelInfo, fmt.Sprintf, format, args)
+}
+
+// Warnf logs a log with format and args in warn level.
+// The msg is formatted only if warn level is enabled.
+func (l *Logger) Warnf(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelWarn, fmt.Sprintf, format, args)
+}
+
+// Errorf logs a log with format and args in error level.
+// The msg is formatted only if error level is enabled.
+func (l *Logger) Errorf(format string, args ...any) {
+	l.logf(context.Background(), slog.LevelError, fmt.Sprintf, format, args)
+}
+
+// Panic logs a log with msg and args in panic level.
+// It syncs the logger and then panics with msg.
+func (l *Logger) Panic(msg string, args ...any) {
+	l.log(context.Background(), defaults.LevelPanic, msg, args...)
+	l.syncBeforeExiting()
+	panic(msg)
+}
+
+// Fatal logs a log with msg and args in fatal level.
+// It syncs the logger and then exits the process with code 1.
+// See defaults.Exit.
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.log(context.Background(), defaults.LevelFatal, msg, args...)
+	l.syncBeforeExiting()
+	defaults.Exit(1)
+}
+
+// DebugContext logs a log with ctx, msg and args in debug level.
+// The ctx will be passed to the handler.
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelDebug, msg, args...)
+}
+
+// InfoContext logs a log with ctx, msg and args in info level.
+// The ctx will be passed to the handler.
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelInfo, msg, args...)
+}
+
+// WarnContext logs a log with ctx, msg and args in warn level.
+// The ctx will be passed to the handler.
+func (l *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelWarn, msg, args...)
+}
+
+// ErrorContext logs a log with ctx, msg and args in error level.
+// The ctx will be passed to the handler.
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelError, msg, args...)
+}
+
+// Log logs a log with ctx, msg and args in given level.
+// The ctx will be passed to the handler.
+func (l *Logger) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l.log(ctx, level, msg, args...)
 }
 
 // Printf logs a log with format and args in print level.
 // It a old-school way to log.
 func (l *Logger) Printf(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	l.log(defaults.LevelPrint, msg)
+	l.logf(context.Background(), defaults.LevelPrint, fmt.Sprintf, format, args)
 }
 
 // Print logs a log with args in print level.
 // It a old-school way to log.
 func (l *Logger) Print(args ...interface{}) {
-	msg := fmt.Sprint(args...)
-	l.log(defaults.LevelPrint, msg)
+	l.logf(context.Background(), defaults.LevelPrint, sprint, "", args)
 }
 
 // Println logs a log with args in print level.
 // It a old-school way to log.
 func (l *Logger) Println(args ...interface{}) {
-	msg := fmt.Sprintln(args...)
-	l.log(defaults.LevelPrint, msg)
+	l.logf(context.Background(), defaults.LevelPrint, sprintln, "", args)
+}
+
+// syncBeforeExiting syncs the logger so data in buffer won't be lost before exiting.
+func (l *Logger) syncBeforeExiting() {
+	if err := l.Sync(); err != nil {
+		defaults.HandleError("logit.Logger.Sync", err)
+	}
 }
 
 // Sync syncs the logger and returns an error if failed.
+// It does nothing after the logger is closed.
 func (l *Logger) Sync() error {
+	// Closing waits for syncing so the syncer won't be synced while closing.
+	if !l.lifecycle.acquire() {
+		return nil
+	}
+
+	defer l.lifecycle.release()
+
 	return l.syncer.Sync()
 }
 
 // Close closes the logger and returns an error if failed.
+// It's safe to call Close several times in several goroutines, and only the first call will close the logger.
+// Loggers derived from the logger share the same lifecycle, so they will be closed too.
+// Logging after closing will be reported to defaults.HandleError instead of being handled.
 func (l *Logger) Close() error {
-	if err := l.Sync(); err != nil {
-		return err
-	}
+	return l.lifecycle.close(func() error {
+		if err := l.syncer.Sync(); err != nil {
+			return err
+		}
 
-	return l.closer.Close()
+		return l.closer.Close()
+	})
 }
@@ -16,11 +16,14 @@ package logit
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
 	"log/slog"
 	"strings"
 	"testing"
 
+	"github.com/FishGoddess/logit/defaults"
 	"github.com/FishGoddess/logit/handler"
 )
 
@@ -78,15 +81,13 @@ func TestLoggerClone(t *testing.T) {
 	}
 }
 
-// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerNewAttrs$
-func TestLoggerNewAttrs(t *testing.T) {
-	logger := NewLogger()
-
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewAttrs$
+func TestNewAttrs(t *testing.T) {
 	args := []any{
 		"key1", 123, "key2", "456", slog.Bool("key3", true), 666, "key4",
 	}
 
-	attrs := logger.newAttrs(args)
+	attrs := newAttrs(args)
 	if len(attrs) != 5 {
 		t.Fatalf("len(attrs) %d != 5", len(attrs))
 	}
@@ -160,27 +161,179 @@ func TestLoggerWithGroup(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestMatchLevelOverride$
+func TestMatchLevelOverride(t *testing.T) {
+	overrides := map[string]slog.Level{
+		"":        slog.LevelError,
+		"db":      slog.LevelDebug,
+		"db.pool": slog.LevelWarn,
+	}
+
+	tests := []struct {
+		name  string
+		level slog.Level
+		ok    bool
+	}{
+		{name: "", ok: false},
+		{name: "http", ok: false},
+		{name: "dbx", ok: false},
+		{name: "db", level: slog.LevelDebug, ok: true},
+		{name: "db.conn", level: slog.LevelDebug, ok: true},
+		{name: "db.pool", level: slog.LevelWarn, ok: true},
+		{name: "db.pool.idle", level: slog.LevelWarn, ok: true},
+	}
+
+	for _, tt := range tests {
+		level, ok := matchLevelOverride(overrides, tt.name)
+		if ok != tt.ok {
+			t.Fatalf("name %s: ok %+v != tt.ok %+v", tt.name, ok, tt.ok)
+		}
+
+		if level != tt.level {
+			t.Fatalf("name %s: level %+v != tt.level %+v", tt.name, level, tt.level)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerNamed$
+func TestLoggerNamed(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	overrides := map[string]slog.Level{"db": slog.LevelDebug}
+	logger := NewLogger(WithInfoLevel(), WithTextHandler(), WithWriter(buffer), WithLevelOverrides(overrides))
+
+	if newLogger := logger.Named(""); newLogger != logger {
+		t.Fatalf("newLogger %+v != logger %+v", newLogger, logger)
+	}
+
+	dbLogger := logger.Named("db").Named("pool")
+	if dbLogger.Name() != "db.pool" {
+		t.Fatalf("dbLogger.Name() %s != db.pool", dbLogger.Name())
+	}
+
+	httpLogger := logger.Named("http")
+	if httpLogger.Name() != "http" {
+		t.Fatalf("httpLogger.Name() %s != http", httpLogger.Name())
+	}
+
+	logger.Debug("debug msg")
+	httpLogger.Debug("debug msg")
+	dbLogger.Debug("debug msg")
+	httpLogger.Info("info msg")
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := `level=DEBUG msg="debug msg" logger=db.pool level=INFO msg="info msg" logger=http`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerNamedSetLevel$
+func TestLoggerNamedSetLevel(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	overrides := map[string]slog.Level{"db": slog.LevelDebug}
+	logger := NewLogger(WithInfoLevel(), WithTextHandler(), WithWriter(buffer), WithLevelOverrides(overrides))
+	dbLogger := logger.Named("db")
+
+	logger.SetLevel(slog.LevelWarn)
+	logger.Info("info msg")
+	dbLogger.Debug("debug msg")
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := `level=DEBUG msg="debug msg" logger=db`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+
+	// Outputs with higher levels shouldn't be bypassed by the level override of name.
+	buffer.Reset()
+
+	logger = NewLogger(WithInfoLevel(), WithLevelOverrides(overrides), WithOutputs(Output{Level: slog.LevelError, Handler: handler.Text, Writer: buffer}))
+	dbLogger = logger.Named("db")
+
+	if dbLogger.DebugEnabled() {
+		t.Fatal("dbLogger enabled debug")
+	}
+
+	dbLogger.Debug("debug msg")
+	dbLogger.Warn("warn msg")
+	dbLogger.Error("error msg")
+
+	got = strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want = `level=ERROR msg="error msg" logger=db`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerEnabled$
 func TestLoggerEnabled(t *testing.T) {
+	ctx := context.Background()
 	logger := NewLogger(WithErrorLevel())
 
-	if logger.enabled(slog.LevelDebug) {
+	if logger.enabled(ctx, slog.LevelDebug) {
 		t.Fatal("logger enabled debug")
 	}
 
-	if logger.enabled(slog.LevelInfo) {
+	if logger.enabled(ctx, slog.LevelInfo) {
 		t.Fatal("logger enabled info")
 	}
 
-	if logger.enabled(slog.LevelWarn) {
+	if logger.enabled(ctx, slog.LevelWarn) {
 		t.Fatal("logger enabled warn")
 	}
 
-	if !logger.enabled(slog.LevelError) {
+	if !logger.enabled(ctx, slog.LevelError) {
 		t.Fatal("logger enabled error")
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSetLevel$
+func TestLoggerSetLevel(t *testing.T) {
+	handlers := []Option{WithTapeHandler(), WithTextHandler(), WithJsonHandler()}
+
+	for _, withHandler := range handlers {
+		buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+		logger := NewLogger(WithInfoLevel(), WithWriter(buffer), withHandler)
+		childLogger := logger.With("key", "value").WithGroup("group")
+
+		if logger.Level() != slog.LevelInfo {
+			t.Fatalf("logger.Level() %v != slog.LevelInfo", logger.Level())
+		}
+
+		logger.Debug("debug msg")
+		childLogger.Debug("debug msg")
+
+		if buffer.Len() > 0 {
+			t.Fatalf("buffer.Len() %d > 0", buffer.Len())
+		}
+
+		logger.SetLevel(slog.LevelDebug)
+
+		if childLogger.Level() != slog.LevelDebug {
+			t.Fatalf("childLogger.Level() %v != slog.LevelDebug", childLogger.Level())
+		}
+
+		logger.Debug("debug msg")
+		childLogger.Debug("debug msg")
+
+		lines := strings.Count(buffer.String(), "\n")
+		if lines != 2 {
+			t.Fatalf("lines %d != 2", lines)
+		}
+
+		childLogger.SetLevel(slog.LevelError)
+		logger.Warn("warn msg")
+
+		lines = strings.Count(buffer.String(), "\n")
+		if lines != 2 {
+			t.Fatalf("lines %d != 2", lines)
+		}
+	}
+}
+
 func removeTimeAndSource(str string) string {
 	str = strings.ReplaceAll(str, "\n", " ")
 	strs := strings.Split(str, " ")
@@ -239,6 +392,225 @@ func TestLogger(t *testing.T) {
 	}
 }
 
+type testContextKey struct{}
+
+type testContextHandler struct {
+	slog.Handler
+
+	enabledValues []any
+	handleValues  []any
+}
+
+func (tch *testContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	tch.enabledValues = append(tch.enabledValues, ctx.Value(testContextKey{}))
+	return true
+}
+
+func (tch *testContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	tch.handleValues = append(tch.handleValues, ctx.Value(testContextKey{}))
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerContext$
+func TestLoggerContext(t *testing.T) {
+	handler := &testContextHandler{}
+	logger := &Logger{handler: handler}
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "value")
+	logger.DebugContext(ctx, "debug msg")
+	logger.InfoContext(ctx, "info msg")
+	logger.WarnContext(ctx, "warn msg")
+	logger.ErrorContext(ctx, "error msg")
+	logger.Log(ctx, slog.LevelInfo, "log msg")
+
+	if len(handler.enabledValues) != 5 {
+		t.Fatalf("len(handler.enabledValues) %d != 5", len(handler.enabledValues))
+	}
+
+	if len(handler.handleValues) != 5 {
+		t.Fatalf("len(handler.handleValues) %d != 5", len(handler.handleValues))
+	}
+
+	for i := 0; i < 5; i++ {
+		if handler.enabledValues[i] != "value" {
+			t.Fatalf("handler.enabledValues[%d] %+v != value", i, handler.enabledValues[i])
+		}
+
+		if handler.handleValues[i] != "value" {
+			t.Fatalf("handler.handleValues[%d] %+v != value", i, handler.handleValues[i])
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerContextExtractor$
+func TestLoggerContextExtractor(t *testing.T) {
+	extractor := func(ctx context.Context) []slog.Attr {
+		if requestID, ok := ctx.Value(testContextKey{}).(string); ok {
+			return []slog.Attr{slog.String("request_id", requestID)}
+		}
+
+		return nil
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithTextHandler(), WithContextExtractor(extractor))
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "req-001")
+	ctx = WithContextAttrs(ctx, "user_id", 123)
+
+	logger.InfoContext(ctx, "info msg", "key", "value")
+	logger.Info("info msg", "key", "value")
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := `level=INFO msg="info msg" user_id=123 request_id=req-001 key=value level=INFO msg="info msg" key=value`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerHooks$
+func TestLoggerHooks(t *testing.T) {
+	addVersion := func(ctx context.Context, record *slog.Record) bool {
+		record.AddAttrs(slog.String("version", "v1.0.0"))
+		return true
+	}
+
+	mapEOF := func(ctx context.Context, record *slog.Record) bool {
+		record.Attrs(func(attr slog.Attr) bool {
+			if err, ok := attr.Value.Any().(error); ok && err == io.EOF {
+				record.Level = slog.LevelWarn
+				return false
+			}
+
+			return true
+		})
+
+		return true
+	}
+
+	dropHealth := func(ctx context.Context, record *slog.Record) bool {
+		return record.Message != "health check"
+	}
+
+	debugAll := func(ctx context.Context, record *slog.Record) bool {
+		if ctx.Value(testContextKey{}) != nil {
+			record.Level = slog.LevelDebug
+		}
+
+		return true
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(
+		WithInfoLevel(), WithTextHandler(), WithWriter(buffer), WithHooks(addVersion, mapEOF), WithHooks(dropHealth, debugAll),
+	)
+
+	logger.Info("health check")
+	logger.Error("read failed", "err", io.EOF)
+	logger.InfoContext(context.WithValue(context.Background(), testContextKey{}, true), "debug msg")
+	logger.Info("info msg")
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	want := `level=WARN msg="read failed" err=EOF version=v1.0.0 level=INFO msg="info msg" version=v1.0.0`
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerStacktrace$
+func TestLoggerStacktrace(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithWriter(buffer), WithStacktrace(slog.LevelWarn))
+
+	logger.Info("info msg")
+	if strings.Contains(buffer.String(), keyStack) {
+		t.Fatalf("buffer %s contains stack", buffer.String())
+	}
+
+	logger.Warn("warn msg")
+
+	got := buffer.String()
+	if strings.Count(got, "\n") != 2 {
+		t.Fatalf("got %s has wrong lines", got)
+	}
+
+	if !strings.Contains(got, "stack=github.com/FishGoddess/logit.TestLoggerStacktrace ") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if !strings.Contains(got, "logger_test.go:") || !strings.Contains(got, "\\n") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	buffer.Reset()
+	logger = NewLogger(WithWriter(buffer), WithJsonHandler(), WithStacktrace(slog.LevelError))
+	logger.Error("error msg")
+
+	var log struct {
+		Stack []string `json:"stack"`
+	}
+
+	if err := json.Unmarshal(buffer.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log.Stack) <= 0 {
+		t.Fatalf("len(log.Stack) %d <= 0", len(log.Stack))
+	}
+
+	if !strings.HasPrefix(log.Stack[0], "github.com/FishGoddess/logit.TestLoggerStacktrace ") {
+		t.Fatalf("log.Stack[0] %s is wrong", log.Stack[0])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerPanic$
+func TestLoggerPanic(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithBuffer(1024))
+
+	defer func() {
+		r := recover()
+		if r != "panic msg" {
+			t.Fatalf("r %+v != panic msg", r)
+		}
+
+		got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+		if !strings.HasSuffix(got, "PANIC ¦ panic msg ¦ key=value") {
+			t.Fatalf("got %s is wrong", got)
+		}
+	}()
+
+	logger.Panic("panic msg", "key", "value")
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerFatal$
+func TestLoggerFatal(t *testing.T) {
+	exit := defaults.Exit
+	defer func() {
+		defaults.Exit = exit
+	}()
+
+	exitCode := 0
+	defaults.Exit = func(code int) {
+		exitCode = code
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithBuffer(1024))
+	logger.Fatal("fatal msg", "key", "value")
+
+	if exitCode != 1 {
+		t.Fatalf("exitCode %d != 1", exitCode)
+	}
+
+	got := strings.TrimSpace(removeTimeAndSource(buffer.String()))
+	if !strings.HasSuffix(got, "FATAL ¦ fatal msg ¦ key=value") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerSync$
 func TestLoggerSync(t *testing.T) {
 	syncer := &testSyncer{
@@ -0,0 +1,170 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logtest
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Entry is a record handled by the handler of logtest.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+
+	// Attrs are the resolved attrs of record, including the ones added by logger.With.
+	// Attrs in groups are nested in group attrs, so use AttrValue to find them with a path like "group.key".
+	Attrs []slog.Attr
+
+	// Source is where the record is logged, and it's nil if the record doesn't have a pc.
+	Source *slog.Source
+}
+
+// findAttr finds the value of key in attrs.
+// The key can be a path like "group.key" and the exact key is preferred.
+func findAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	for _, attr := range attrs {
+		if attr.Value.Kind() != slog.KindGroup {
+			continue
+		}
+
+		subKey, ok := strings.CutPrefix(key, attr.Key+".")
+		if !ok {
+			continue
+		}
+
+		if value, ok := findAttr(attr.Value.Group(), subKey); ok {
+			return value, true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// AttrValue returns the value of key in attrs and reports whether the key is found.
+// Use a path like "group.key" to find attrs in groups.
+func (e Entry) AttrValue(key string) (slog.Value, bool) {
+	return findAttr(e.Attrs, key)
+}
+
+// hasAttrs reports whether the entry has all attrs with the same values.
+func (e Entry) hasAttrs(attrs []slog.Attr) bool {
+	for _, attr := range attrs {
+		value, ok := e.AttrValue(attr.Key)
+		if !ok || !value.Equal(attr.Value.Resolve()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the entry in a format like "INFO msg key=value".
+func (e Entry) String() string {
+	var builder strings.Builder
+	builder.WriteString(e.Level.String())
+	builder.WriteString(" ")
+	builder.WriteString(e.Message)
+
+	for _, attr := range e.Attrs {
+		builder.WriteString(" ")
+		builder.WriteString(attr.String())
+	}
+
+	return builder.String()
+}
+
+// Entries is a list of entries in the order they are handled.
+type Entries []Entry
+
+// FilterLevel returns the entries in level.
+func (es Entries) FilterLevel(level slog.Level) Entries {
+	var filtered Entries
+	for _, entry := range es {
+		if entry.Level == level {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// FilterMessage returns the entries with msg.
+func (es Entries) FilterMessage(msg string) Entries {
+	var filtered Entries
+	for _, entry := range es {
+		if entry.Message == msg {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// argsToAttrs converts args to attrs in the way of slog.Logger.
+func argsToAttrs(args []any) []slog.Attr {
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "", 0)
+	record.Add(args...)
+
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	return attrs
+}
+
+// AssertLogged asserts there is an entry in level with msg and args, and returns the entry found.
+// Args are key-value pairs or slog.Attrs like the ones passed to logger, and the entry should have all of them with the same values.
+// It reports an error to t and returns false if not found.
+func (es Entries) AssertLogged(t testing.TB, level slog.Level, msg string, args ...any) (Entry, bool) {
+	t.Helper()
+
+	attrs := argsToAttrs(args)
+	for _, entry := range es.FilterLevel(level).FilterMessage(msg) {
+		if entry.hasAttrs(attrs) {
+			return entry, true
+		}
+	}
+
+	var logged strings.Builder
+	for _, entry := range es {
+		logged.WriteString("\n\t")
+		logged.WriteString(entry.String())
+	}
+
+	t.Errorf("logtest: no entry %s %s %v logged in %d entries:%s", level, msg, attrs, len(es), logged.String())
+	return Entry{}, false
+}
+
+// String returns the entries in lines.
+func (es Entries) String() string {
+	lines := make([]string, 0, len(es))
+	for _, entry := range es {
+		lines = append(lines, entry.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logtest
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+type testFailedTB struct {
+	testing.TB
+
+	errors []string
+}
+
+func (tft *testFailedTB) Helper() {}
+
+func (tft *testFailedTB) Errorf(format string, args ...any) {
+	tft.errors = append(tft.errors, fmt.Sprintf(format, args...))
+}
+
+func newTestEntries() Entries {
+	entries := Entries{
+		{Level: slog.LevelDebug, Message: "debug msg", Attrs: []slog.Attr{slog.Int("key", 1)}},
+		{Level: slog.LevelInfo, Message: "info msg", Attrs: []slog.Attr{slog.Group("group", slog.String("key", "value"))}},
+		{Level: slog.LevelInfo, Message: "info msg", Attrs: []slog.Attr{slog.String("group.key", "dot")}},
+		{Level: slog.LevelError, Message: "error msg"},
+	}
+
+	return entries
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEntryAttrValue$
+func TestEntryAttrValue(t *testing.T) {
+	entries := newTestEntries()
+
+	value, ok := entries[0].AttrValue("key")
+	if !ok || value.Int64() != 1 {
+		t.Fatalf("value %+v is wrong", value)
+	}
+
+	value, ok = entries[1].AttrValue("group.key")
+	if !ok || value.String() != "value" {
+		t.Fatalf("value %+v is wrong", value)
+	}
+
+	value, ok = entries[2].AttrValue("group.key")
+	if !ok || value.String() != "dot" {
+		t.Fatalf("value %+v is wrong", value)
+	}
+
+	if _, ok = entries[1].AttrValue("group.unknown"); ok {
+		t.Fatal("group.unknown shouldn't be found")
+	}
+
+	if _, ok = entries[3].AttrValue("key"); ok {
+		t.Fatal("key shouldn't be found")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEntriesFilter$
+func TestEntriesFilter(t *testing.T) {
+	entries := newTestEntries()
+
+	if got := entries.FilterLevel(slog.LevelInfo); len(got) != 2 {
+		t.Fatalf("len(got) %d != 2", len(got))
+	}
+
+	if got := entries.FilterLevel(slog.LevelWarn); len(got) != 0 {
+		t.Fatalf("len(got) %d != 0", len(got))
+	}
+
+	if got := entries.FilterMessage("error msg"); len(got) != 1 || got[0].Level != slog.LevelError {
+		t.Fatalf("got %+v is wrong", got)
+	}
+
+	if got := entries.FilterLevel(slog.LevelDebug).FilterMessage("info msg"); len(got) != 0 {
+		t.Fatalf("len(got) %d != 0", len(got))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestEntriesAssertLogged$
+func TestEntriesAssertLogged(t *testing.T) {
+	entries := newTestEntries()
+
+	entry, ok := entries.AssertLogged(t, slog.LevelInfo, "info msg", slog.Group("group", "key", "value"))
+	if !ok || entry.Attrs[0].Key != "group" {
+		t.Fatalf("entry %+v is wrong", entry)
+	}
+
+	entry, ok = entries.AssertLogged(t, slog.LevelInfo, "info msg", "group.key", "dot")
+	if !ok || entry.Attrs[0].Key != "group.key" {
+		t.Fatalf("entry %+v is wrong", entry)
+	}
+
+	tb := &testFailedTB{TB: t}
+	if _, ok = entries.AssertLogged(tb, slog.LevelDebug, "debug msg", "key", 2); ok {
+		t.Fatal("assert logged should be failed")
+	}
+
+	if len(tb.errors) != 1 {
+		t.Fatalf("len(tb.errors) %d != 1", len(tb.errors))
+	}
+
+	want := "logtest: no entry DEBUG debug msg [key=2] logged in 4 entries:\n\tDEBUG debug msg key=1\n\tINFO info msg group=[key=value]\n\tINFO info msg group.key=dot\n\tERROR error msg"
+	if tb.errors[0] != want {
+		t.Fatalf("tb.errors[0] %s != want %s", tb.errors[0], want)
+	}
+}
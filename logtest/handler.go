@@ -0,0 +1,202 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logtest
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+
+	"github.com/FishGoddess/logit/handler"
+)
+
+// handlerScope is a group or some attrs added to handler.
+type handlerScope struct {
+	group string
+	attrs []slog.Attr
+}
+
+// Handler is a handler recording all records to a recorder.
+// Attrs will be resolved and replaced by opts.ReplaceAttr before recording.
+type Handler struct {
+	recorder *Recorder
+	opts     slog.HandlerOptions
+	groups   []string
+	scopes   []handlerScope
+	mirror   slog.Handler
+}
+
+// NewHandler returns a new handler recording records to recorder.
+// Records will also be written to the mirror of recorder by a tape handler if the mirror is set.
+func NewHandler(recorder *Recorder, opts *slog.HandlerOptions) *Handler {
+	if opts == nil {
+		opts = new(slog.HandlerOptions)
+	}
+
+	h := &Handler{
+		recorder: recorder,
+		opts:     *opts,
+	}
+
+	if recorder.mirror != nil {
+		h.mirror = handler.NewTapeHandler(recorder.mirror, opts)
+	}
+
+	return h
+}
+
+// Recorder returns the recorder of handler.
+func (h *Handler) Recorder() *Recorder {
+	return h.recorder
+}
+
+// Enabled reports whether the handler handles records in level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+
+	return level >= minLevel
+}
+
+// appendAttr resolves and replaces attr, and appends it to attrs.
+// Groups with empty keys will be inlined and empty groups will be ignored like slog does.
+func (h *Handler) appendAttr(attrs []slog.Attr, groups []string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		if attr.Key == "" {
+			return h.appendAttrs(attrs, groups, groupAttrs)
+		}
+
+		subGroups := append(slices.Clip(groups), attr.Key)
+		if groupAttrs = h.appendAttrs(nil, subGroups, groupAttrs); len(groupAttrs) <= 0 {
+			return attrs
+		}
+
+		return append(attrs, slog.Attr{Key: attr.Key, Value: slog.GroupValue(groupAttrs...)})
+	}
+
+	if h.opts.ReplaceAttr != nil {
+		attr = h.opts.ReplaceAttr(groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	return append(attrs, attr)
+}
+
+func (h *Handler) appendAttrs(attrs []slog.Attr, groups []string, newAttrs []slog.Attr) []slog.Attr {
+	for _, attr := range newAttrs {
+		attrs = h.appendAttr(attrs, groups, attr)
+	}
+
+	return attrs
+}
+
+// source returns the source of pc or nil if pc is 0.
+func source(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	source := &slog.Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+
+	return source
+}
+
+// Handle records the record to recorder.
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = h.appendAttr(attrs, h.groups, attr)
+		return true
+	})
+
+	// Wrap attrs with scopes from inside to outside, so attrs of record are in all groups.
+	for i := len(h.scopes) - 1; i >= 0; i-- {
+		scope := h.scopes[i]
+
+		if scope.group == "" {
+			attrs = append(slices.Clip(scope.attrs), attrs...)
+			continue
+		}
+
+		if len(attrs) > 0 {
+			attrs = []slog.Attr{{Key: scope.group, Value: slog.GroupValue(attrs...)}}
+		}
+	}
+
+	entry := Entry{
+		Time:    record.Time,
+		Level:   record.Level,
+		Message: record.Message,
+		Attrs:   attrs,
+		Source:  source(record.PC),
+	}
+
+	h.recorder.record(entry)
+
+	if h.mirror != nil {
+		return h.mirror.Handle(ctx, record)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a new handler with attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) <= 0 {
+		return h
+	}
+
+	newHandler := *h
+	newHandler.scopes = append(slices.Clip(h.scopes), handlerScope{attrs: h.appendAttrs(nil, h.groups, attrs)})
+
+	if h.mirror != nil {
+		newHandler.mirror = h.mirror.WithAttrs(attrs)
+	}
+
+	return &newHandler
+}
+
+// WithGroup returns a new handler with group.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	newHandler := *h
+	newHandler.groups = append(slices.Clip(h.groups), name)
+	newHandler.scopes = append(slices.Clip(h.scopes), handlerScope{group: name})
+
+	if h.mirror != nil {
+		newHandler.mirror = h.mirror.WithGroup(name)
+	}
+
+	return &newHandler
+}
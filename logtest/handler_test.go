@@ -0,0 +1,121 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logtest
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHandler$
+func TestHandler(t *testing.T) {
+	replaceAttr := func(groups []string, attr slog.Attr) slog.Attr {
+		if attr.Key == "drop" {
+			return slog.Attr{}
+		}
+
+		if attr.Key == "path" {
+			attr.Value = slog.StringValue(strings.Join(groups, "."))
+		}
+
+		return attr
+	}
+
+	recorder := NewRecorder()
+	handler := NewHandler(recorder, &slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: replaceAttr})
+
+	if handler.Recorder() != recorder {
+		t.Fatal("handler.Recorder() != recorder")
+	}
+
+	logger := slog.New(handler)
+	logger.Debug("debug msg")
+
+	logger = logger.With("id", 1, "drop", true).WithGroup("request").With("path", "").WithGroup("empty")
+	logger.Info("info msg", "lazy", slog.AnyValue(testLogValuer("resolved")), "path", "", slog.Group("", "inline", true), slog.Group("nothing"))
+	logger.Info("no attrs")
+
+	entries := recorder.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) %d != 2", len(entries))
+	}
+
+	got := entries.String()
+	want := "INFO info msg id=1 request=[path=request empty=[lazy=resolved path=request.empty inline=true]]\nINFO no attrs id=1 request=[path=request]"
+
+	if got != want {
+		t.Fatalf("got %s != want %s", got, want)
+	}
+}
+
+type testLogValuer string
+
+func (tlv testLogValuer) LogValue() slog.Value {
+	return slog.StringValue(string(tlv))
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHandlerSource$
+func TestHandlerSource(t *testing.T) {
+	recorder := NewRecorder()
+	handler := NewHandler(recorder, nil)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "no source", 0)
+	if err := handler.Handle(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(handler)
+	logger.Info("with source")
+
+	entries := recorder.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) %d != 2", len(entries))
+	}
+
+	if entries[0].Source != nil {
+		t.Fatalf("entries[0].Source %+v != nil", entries[0].Source)
+	}
+
+	source := entries[1].Source
+	if source == nil || !strings.HasSuffix(source.File, "handler_test.go") || !strings.HasSuffix(source.Function, "TestHandlerSource") {
+		t.Fatalf("source %+v is wrong", source)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestHandlerMirror$
+func TestHandlerMirror(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	recorder := NewRecorder()
+	recorder.mirror = buffer
+
+	logger := slog.New(NewHandler(recorder, nil))
+	logger.With("id", 1).Info("mirror msg", slog.Group("request", "path", "/"))
+
+	got := buffer.String()
+	want := "INFO ¦ mirror msg ¦ id=1 ¦ request.path=/\n"
+
+	if !strings.HasSuffix(got, want) {
+		t.Fatalf("got %s doesn't end with %s", got, want)
+	}
+
+	if recorder.Len() != 1 {
+		t.Fatalf("recorder.Len() %d != 1", recorder.Len())
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logtest provides a logger recording all logs in memory, so tests can assert on logs without parsing outputs.
+package logtest
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+	"github.com/FishGoddess/logit/handler"
+)
+
+const (
+	// HandlerName is the name of handler registered by logtest, and it records logs to the recorder passed as writer.
+	// See logit.WithHandler and logit.WithWriter.
+	HandlerName = "logtest"
+)
+
+func init() {
+	newHandler := func(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+		recorder, ok := w.(*Recorder)
+		if !ok {
+			recorder = NewRecorder()
+		}
+
+		return NewHandler(recorder, opts)
+	}
+
+	if err := handler.Register(HandlerName, newHandler); err != nil {
+		panic(err)
+	}
+}
+
+// Recorder records entries handled by handlers in memory.
+// It's safe to use a recorder in multiple goroutines.
+type Recorder struct {
+	entries Entries
+	mirror  io.Writer
+
+	lock sync.RWMutex
+}
+
+// NewRecorder returns a new recorder.
+func NewRecorder() *Recorder {
+	return new(Recorder)
+}
+
+func (r *Recorder) record(entry Entry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.entries = append(r.entries, entry)
+}
+
+// Write discards p so a recorder can be used as the writer of logger, see logit.WithWriter.
+func (r *Recorder) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+// Entries returns a copy of all entries recorded.
+func (r *Recorder) Entries() Entries {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	entries := make(Entries, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Len returns the count of entries recorded.
+func (r *Recorder) Len() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return len(r.entries)
+}
+
+// Reset removes all entries recorded.
+func (r *Recorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.entries = nil
+}
+
+// FilterLevel returns the entries in level.
+func (r *Recorder) FilterLevel(level slog.Level) Entries {
+	return r.Entries().FilterLevel(level)
+}
+
+// FilterMessage returns the entries with msg.
+func (r *Recorder) FilterMessage(msg string) Entries {
+	return r.Entries().FilterMessage(msg)
+}
+
+// AssertLogged asserts there is an entry in level with msg and args, see Entries.AssertLogged.
+func (r *Recorder) AssertLogged(t testing.TB, level slog.Level, msg string, args ...any) (Entry, bool) {
+	t.Helper()
+
+	return r.Entries().AssertLogged(t, level, msg, args...)
+}
+
+// testLogWriter writes logs to t.Log.
+type testLogWriter struct {
+	t testing.TB
+}
+
+func (tlw testLogWriter) Write(p []byte) (n int, err error) {
+	tlw.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+type config struct {
+	testLog    bool
+	loggerOpts []logit.Option
+}
+
+// Option is a function for setting config.
+type Option func(conf *config)
+
+func (o Option) applyTo(conf *config) {
+	o(conf)
+}
+
+// WithTestLog mirrors logs to t.Log in tape format, so you can see them with "go test -v" or when tests fail.
+func WithTestLog() Option {
+	return func(conf *config) {
+		conf.testLog = true
+	}
+}
+
+// WithLoggerOptions sets options of logger, like logit.WithLevel and logit.WithReplaceAttr.
+// Options of handler and writer will be overwritten since logs must be recorded by the recorder.
+func WithLoggerOptions(opts ...logit.Option) Option {
+	return func(conf *config) {
+		conf.loggerOpts = append(conf.loggerOpts, opts...)
+	}
+}
+
+// NewLogger returns a new logger recording logs to the recorder returned.
+// The logger logs in debug level with source by default, and it will be closed when t finishes.
+func NewLogger(t testing.TB, opts ...Option) (*logit.Logger, *Recorder) {
+	t.Helper()
+
+	conf := new(config)
+	for _, opt := range opts {
+		opt.applyTo(conf)
+	}
+
+	recorder := NewRecorder()
+	if conf.testLog {
+		recorder.mirror = testLogWriter{t: t}
+	}
+
+	loggerOpts := []logit.Option{logit.WithDebugLevel(), logit.WithSource()}
+	loggerOpts = append(loggerOpts, conf.loggerOpts...)
+	loggerOpts = append(loggerOpts, logit.WithHandler(HandlerName), logit.WithWriter(recorder))
+
+	logger, err := logit.NewLoggerGracefully(loggerOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		logger.Close()
+	})
+
+	return logger, recorder
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logtest
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/FishGoddess/logit"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewLogger$
+func TestNewLogger(t *testing.T) {
+	logger, recorder := NewLogger(t)
+
+	err := errors.New("oops")
+	logger.Debug("debug msg", "key", 1)
+	logger.WithGroup("request").Info("info msg", "path", "/")
+	logger.Error("error msg", logit.Err(err))
+
+	if recorder.Len() != 3 {
+		t.Fatalf("recorder.Len() %d != 3", recorder.Len())
+	}
+
+	entry, _ := recorder.AssertLogged(t, slog.LevelDebug, "debug msg", "key", 1)
+	if entry.Source == nil || filepath.Base(entry.Source.File) != "logtest_test.go" {
+		t.Fatalf("entry.Source %+v is wrong", entry.Source)
+	}
+
+	recorder.AssertLogged(t, slog.LevelInfo, "info msg", slog.Group("request", "path", "/"))
+
+	entry, _ = recorder.AssertLogged(t, slog.LevelError, "error msg")
+	if value, ok := entry.AttrValue("err"); !ok || value.Any() != err {
+		t.Fatalf("value %+v is wrong", value)
+	}
+
+	if got := recorder.FilterLevel(slog.LevelInfo); len(got) != 1 {
+		t.Fatalf("len(got) %d != 1", len(got))
+	}
+
+	if got := recorder.FilterMessage("error msg"); len(got) != 1 {
+		t.Fatalf("len(got) %d != 1", len(got))
+	}
+
+	recorder.Reset()
+	if recorder.Len() != 0 {
+		t.Fatalf("recorder.Len() %d != 0", recorder.Len())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewLoggerOptions$
+func TestNewLoggerOptions(t *testing.T) {
+	logger, recorder := NewLogger(t, WithTestLog(), WithLoggerOptions(logit.WithWarnLevel()))
+
+	logger.Info("info msg")
+	logger.Warn("warn msg")
+
+	entries := recorder.Entries()
+	if len(entries) != 1 || entries[0].Message != "warn msg" {
+		t.Fatalf("entries %+v is wrong", entries)
+	}
+
+	if _, ok := recorder.mirror.(testLogWriter); !ok {
+		t.Fatalf("recorder.mirror %T isn't testLogWriter", recorder.mirror)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNewLoggerConcurrently$
+func TestNewLoggerConcurrently(t *testing.T) {
+	logger, recorder := NewLogger(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger.Info("concurrent msg", "i", i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	entries := recorder.FilterMessage("concurrent msg")
+	if len(entries) != 16 {
+		t.Fatalf("len(entries) %d != 16", len(entries))
+	}
+
+	for i := 0; i < 16; i++ {
+		recorder.AssertLogged(t, slog.LevelInfo, "concurrent msg", "i", i)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"time"
 
 	"github.com/FishGoddess/logit/defaults"
@@ -61,6 +62,30 @@ func WithErrorLevel() Option {
 	}
 }
 
+// WithLevel sets level to config.
+// It's useful if you want to use a level except debug, info, warn and error, like defaults.LevelFatal.
+func WithLevel(level slog.Level) Option {
+	return func(conf *config) {
+		conf.level = level
+	}
+}
+
+// WithLevelOverrides sets level overrides to config.
+// The key is the name of logger and the value is the level used by loggers with the name.
+// A key matches names in prefix, so "db" at debug level applies to "db" and "db.pool".
+// See Logger.Named.
+func WithLevelOverrides(overrides map[string]slog.Level) Option {
+	return func(conf *config) {
+		if conf.levelOverrides == nil {
+			conf.levelOverrides = make(map[string]slog.Level, len(overrides))
+		}
+
+		for name, level := range overrides {
+			conf.levelOverrides[name] = level
+		}
+	}
+}
+
 // WithWriter sets writer to config.
 // The writer is for writing logs.
 func WithWriter(w io.Writer) Option {
@@ -73,6 +98,14 @@ func WithWriter(w io.Writer) Option {
 	}
 }
 
+// WithNewWriter sets a function creating writer to config.
+// The writer is created when creating the logger, and the error returned will be returned by NewLoggerGracefully.
+func WithNewWriter(newWriter func() (io.Writer, error)) Option {
+	return func(conf *config) {
+		conf.newWriter = newWriter
+	}
+}
+
 // WithStdout sets os.Stdout to config.
 // All logs will be written to stdout.
 func WithStdout() Option {
@@ -134,29 +167,87 @@ func WithRotateFile(path string, opts ...rotate.Option) Option {
 	}
 }
 
-// WithBuffer sets a buffer writer to config.
+// WithNetwork sets network writer to config.
+// All logs will be shipped to address in network, like "tcp" and "127.0.0.1:514", see writer.Network.
+// The writer reconnects with backoff if disconnected, so logging won't fail when the peer is down.
+// Use writer.NetworkOption to customize your network writer, like writer.WithTLS.
+func WithNetwork(network string, address string, opts ...writer.NetworkOption) Option {
+	newWriter := func() (io.Writer, error) {
+		nw, err := writer.Network(network, address, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return nw, nil
+	}
+
+	return func(conf *config) {
+		conf.newWriter = newWriter
+	}
+}
+
+// WithBuffer adds a buffer writer middleware to config.
 // You should specify a buffer size in bytes.
 // The remained data in buffer may discard if you kill the process without syncing or closing the logger.
-func WithBuffer(bufferSize uint64) Option {
+// Use writer.WithFlushInterval to limit the time that data stays in buffer.
+func WithBuffer(bufferSize uint64, opts ...writer.Option) Option {
 	wrapWriter := func(w io.Writer) io.Writer {
-		return writer.Buffer(w, bufferSize)
+		return writer.Buffer(w, bufferSize, opts...)
 	}
 
 	return func(conf *config) {
-		conf.wrapWriter = wrapWriter
+		conf.writerMiddlewares = append(conf.writerMiddlewares, wrapWriter)
 	}
 }
 
-// WithBatch sets a batch writer to config.
+// WithBatch adds a batch writer middleware to config.
 // You should specify a batch size in count.
 // The remained logs in batch may discard if you kill the process without syncing or closing the logger.
-func WithBatch(batchSize uint64) Option {
+// Use writer.WithFlushInterval and writer.WithMaxBatchBytes to limit the time and the bytes of batch.
+func WithBatch(batchSize uint64, opts ...writer.Option) Option {
 	wrapWriter := func(w io.Writer) io.Writer {
-		return writer.Batch(w, batchSize)
+		return writer.Batch(w, batchSize, opts...)
+	}
+
+	return func(conf *config) {
+		conf.writerMiddlewares = append(conf.writerMiddlewares, wrapWriter)
+	}
+}
+
+// WithAsync sets an async writer to config, so logs are written by a background goroutine.
+// The async writer always wraps all other writer middlewares no matter where the option is, so they run in the background goroutine too.
+// Use writer.AsyncOption to customize the queue size and the overflow policy, see writer.Async.
+// The logs in queue will be drained when syncing or closing the logger.
+func WithAsync(opts ...writer.AsyncOption) Option {
+	wrapAsync := func(w io.Writer) io.Writer {
+		return writer.Async(w, opts...)
 	}
 
 	return func(conf *config) {
-		conf.wrapWriter = wrapWriter
+		conf.asyncMiddleware = wrapAsync
+	}
+}
+
+// WithWriterMiddleware adds middlewares wrapping the writer to config.
+// Middlewares are applied in the order they are added, so the first one wraps the writer directly and the last one is the outermost.
+// WithBuffer and WithBatch are also middlewares, so they stack with yours in order.
+// The async writer set by WithAsync is always the outermost one, see WithAsync.
+// The logger syncs every writer in chain which has a Sync method, and closes every writer which has a Close method unless it's wrapped by one.
+// So a middleware implementing io.Closer should close the writer it wraps.
+func WithWriterMiddleware(middlewares ...func(io.Writer) io.Writer) Option {
+	return func(conf *config) {
+		conf.writerMiddlewares = append(conf.writerMiddlewares, middlewares...)
+	}
+}
+
+// WithOutputs sets outputs to config, and records will be fanned out to all outputs.
+// Every output has its own level, handler and writer, like tape to stdout in debug level and json to a file in info level.
+// The handler and writer set by other options will be ignored if outputs are set.
+// Syncing or closing the logger will sync or close all outputs.
+// Notice that don't share a writer which can be closed among outputs, or it will be closed several times.
+func WithOutputs(outputs ...Output) Option {
+	return func(conf *config) {
+		conf.outputs = append(conf.outputs, outputs...)
 	}
 }
 
@@ -196,6 +287,60 @@ func WithReplaceAttr(replaceAttr func(groups []string, attr slog.Attr) slog.Attr
 	}
 }
 
+// WithRedaction sets redaction to config so sensitive values won't be leaked to logs.
+// The keys are sensitive key names which are case-insensitive and can be glob patterns like "*password*", see path.Match.
+// Values of sensitive keys and all attrs in sensitive groups will be masked.
+// The patterns are used to find sensitive parts in values, like PatternJWT, PatternCreditCard and PatternBearerToken.
+// Structs and maps will be marshaled to json, so their fields are redacted too.
+// The mask is the style of masking, see RedactionMask.
+// It works with WithReplaceAttr, and attrs replaced by replaceAttr will be redacted.
+func WithRedaction(keys []string, patterns []*regexp.Regexp, mask RedactionMask) Option {
+	return func(conf *config) {
+		conf.redactor = newRedactor(keys, patterns, mask)
+	}
+}
+
+// WithSampling sets sampling to config.
+// In every tick, the first records with the same level and message will be logged,
+// and then every thereafter record will be logged, others will be dropped.
+// It's useful in high-QPS paths which may flood your disks.
+// Use Logger.Handler to get the sampling handler and its counters, see handler.SamplingHandler.
+func WithSampling(tick time.Duration, first int, thereafter int) Option {
+	return func(conf *config) {
+		conf.samplingTick = tick
+		conf.samplingFirst = first
+		conf.samplingThereafter = thereafter
+	}
+}
+
+// WithDedup sets a dedup window to config.
+// Identical logs in the window will be collapsed into one log and a summary log carrying the repeated count.
+// The summaries will be logged when the window passes and a log comes, or you call Sync/Close.
+// See handler.DedupHandler.
+func WithDedup(window time.Duration) Option {
+	return func(conf *config) {
+		conf.dedupWindow = window
+	}
+}
+
+// WithContextExtractor adds a context extractor to config.
+// All attrs extracted from the context will be added to logs.
+// You can call it more than once to add more extractors, see ContextExtractor.
+func WithContextExtractor(extractor ContextExtractor) Option {
+	return func(conf *config) {
+		conf.contextExtractors = append(conf.contextExtractors, extractor)
+	}
+}
+
+// WithHooks adds hooks to config.
+// Hooks will be called in order before handling a record, and they can mutate or drop the record.
+// You can call it more than once to add more hooks, see Hook.
+func WithHooks(hooks ...Hook) Option {
+	return func(conf *config) {
+		conf.hooks = append(conf.hooks, hooks...)
+	}
+}
+
 // WithSource sets withSource=true to config.
 // All logs will carry their caller information like file and line.
 func WithSource() Option {
@@ -204,6 +349,15 @@ func WithSource() Option {
 	}
 }
 
+// WithStacktrace sets withStacktrace=true and the min level of stacktrace to config.
+// All logs whose level is greater than or equal to minLevel will carry the stack of caller.
+func WithStacktrace(minLevel slog.Level) Option {
+	return func(conf *config) {
+		conf.withStacktrace = true
+		conf.stacktraceLevel = minLevel
+	}
+}
+
 // WithPID sets withPID=true to config.
 // All logs will carry the process id.
 func WithPID() Option {
@@ -214,12 +368,33 @@ func WithPID() Option {
 
 // WithSyncTimer sets a sync timer duration to config.
 // It will call Sync() so it depends on the handler used by logger.
+// Only one timer runs in the shorter interval if FsyncInterval is also used, see WithDurability.
 func WithSyncTimer(d time.Duration) Option {
 	return func(conf *config) {
 		conf.syncTimer = d
 	}
 }
 
+// WithDurability sets the durability policy to config.
+// It decides when logs are synced to stable storage, see FsyncOnSync, FsyncNever, FsyncInterval and FsyncEveryRecord.
+func WithDurability(policy DurabilityPolicy) Option {
+	return func(conf *config) {
+		conf.durability = policy
+	}
+}
+
+// WithShutdownSignals sets signals to shut down the logger to config.
+// The logger will be synced and closed when receiving one of signals, and then the signal will be raised again.
+// So the process will be terminated by the signal as usual, or handled by your own handlers registered with signal.Notify.
+// Notice that your handlers will receive the signal twice, so use Logger.Close directly if you handle signals yourself.
+// SIGINT and SIGTERM will be used if no signals are given.
+func WithShutdownSignals(signals ...os.Signal) Option {
+	return func(conf *config) {
+		conf.withShutdownSignals = true
+		conf.shutdownSignals = signals
+	}
+}
+
 // ProductionOptions returns some options that we think they are useful in production.
 // We recommend you to use them, so we provide this convenient way to create such a logger.
 func ProductionOptions() []Option {
@@ -16,13 +16,20 @@ package logit
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/FishGoddess/logit/defaults"
 	"github.com/FishGoddess/logit/handler"
 	"github.com/FishGoddess/logit/rotate"
 	"github.com/FishGoddess/logit/writer"
@@ -68,6 +75,35 @@ func TestWithErrorLevel(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLevel$
+func TestWithLevel(t *testing.T) {
+	conf := &config{level: slog.LevelDebug}
+	WithLevel(defaults.LevelFatal).applyTo(conf)
+
+	if conf.level != defaults.LevelFatal {
+		t.Fatalf("conf.level %+v != defaults.LevelFatal", conf.level)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithLevelOverrides$
+func TestWithLevelOverrides(t *testing.T) {
+	conf := &config{levelOverrides: nil}
+	WithLevelOverrides(map[string]slog.Level{"db": slog.LevelDebug}).applyTo(conf)
+	WithLevelOverrides(map[string]slog.Level{"http": slog.LevelError}).applyTo(conf)
+
+	if len(conf.levelOverrides) != 2 {
+		t.Fatalf("len(conf.levelOverrides) %d != 2", len(conf.levelOverrides))
+	}
+
+	if conf.levelOverrides["db"] != slog.LevelDebug {
+		t.Fatalf("conf.levelOverrides[db] %+v != slog.LevelDebug", conf.levelOverrides["db"])
+	}
+
+	if conf.levelOverrides["http"] != slog.LevelError {
+		t.Fatalf("conf.levelOverrides[http] %+v != slog.LevelError", conf.levelOverrides["http"])
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithWriter$
 func TestWithWriter(t *testing.T) {
 	conf := &config{newWriter: nil}
@@ -83,6 +119,26 @@ func TestWithWriter(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithNewWriter$
+func TestWithNewWriter(t *testing.T) {
+	conf := &config{newWriter: nil}
+	WithNewWriter(func() (io.Writer, error) { return os.Stderr, nil }).applyTo(conf)
+
+	w, err := conf.newWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w != os.Stderr {
+		t.Fatalf("w %+v != os.Stderr", w)
+	}
+
+	wantErr := errors.New("new writer failed")
+	if _, err = NewLoggerGracefully(WithNewWriter(func() (io.Writer, error) { return nil, wantErr })); err != wantErr {
+		t.Fatalf("err %+v != wantErr %+v", err, wantErr)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithStdout$
 func TestWithStdout(t *testing.T) {
 	conf := &config{newWriter: nil}
@@ -185,13 +241,61 @@ func TestWithRotateFile(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithNetwork$
+func TestWithNetwork(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	conf := &config{newWriter: nil}
+	WithNetwork("udp", conn.LocalAddr().String()).applyTo(conf)
+
+	w, err := conf.newWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nw, ok := w.(*writer.NetworkWriter)
+	if !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	defer nw.Close()
+
+	text := t.Name()
+	if _, err = w.Write([]byte(text)); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	data := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data[:n]) != text {
+		t.Fatalf("string(data[:n]) %s != text %s", data[:n], text)
+	}
+
+	WithNetwork("http", "127.0.0.1:80").applyTo(conf)
+
+	if _, err = conf.newWriter(); err == nil {
+		t.Fatal("creating network writer of http should be failed")
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithBuffer$
 func TestWithBuffer(t *testing.T) {
-	conf := &config{wrapWriter: nil}
+	conf := &config{writerMiddlewares: nil}
 	WithBuffer(64).applyTo(conf)
 
 	buffer := bytes.NewBuffer(make([]byte, 0, 128))
-	w := conf.wrapWriter(buffer)
+	w := conf.writerMiddlewares[0](buffer)
 
 	ww, ok := w.(*writer.BufferWriter)
 	if !ok {
@@ -231,11 +335,11 @@ func TestWithBuffer(t *testing.T) {
 
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithBatch$
 func TestWithBatch(t *testing.T) {
-	conf := &config{wrapWriter: nil}
+	conf := &config{writerMiddlewares: nil}
 	WithBatch(16).applyTo(conf)
 
 	buffer := bytes.NewBuffer(make([]byte, 0, 256))
-	w := conf.wrapWriter(buffer)
+	w := conf.writerMiddlewares[0](buffer)
 
 	bw, ok := w.(*writer.BatchWriter)
 	if !ok {
@@ -337,6 +441,103 @@ func TestWithReplaceAttr(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSampling$
+func TestWithSampling(t *testing.T) {
+	conf := &config{samplingTick: 0}
+	WithSampling(time.Second, 10, 100).applyTo(conf)
+
+	if conf.samplingTick != time.Second {
+		t.Fatalf("conf.samplingTick %v != time.Second", conf.samplingTick)
+	}
+
+	if conf.samplingFirst != 10 {
+		t.Fatalf("conf.samplingFirst %d != 10", conf.samplingFirst)
+	}
+
+	if conf.samplingThereafter != 100 {
+		t.Fatalf("conf.samplingThereafter %d != 100", conf.samplingThereafter)
+	}
+
+	conf.handler = handler.Tape
+	conf.newWriter = func() (io.Writer, error) { return io.Discard, nil }
+
+	h, _, _, err := conf.newHandler(conf.level)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := h.(*handler.SamplingHandler); !ok {
+		t.Fatalf("handler type %T is wrong", h)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithDedup$
+func TestWithDedup(t *testing.T) {
+	conf := &config{dedupWindow: 0}
+	WithDedup(time.Second).applyTo(conf)
+
+	if conf.dedupWindow != time.Second {
+		t.Fatalf("conf.dedupWindow %v != time.Second", conf.dedupWindow)
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithDedup(time.Minute))
+
+	for i := 0; i < 10; i++ {
+		logger.Error("db down", "err", "timeout")
+	}
+
+	if lines := strings.Count(buffer.String(), "db down"); lines != 1 {
+		t.Fatalf("lines %d != 1", lines)
+	}
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if lines := strings.Count(buffer.String(), "repeated=9"); lines != 1 {
+		t.Fatalf("lines %d != 1", lines)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithContextExtractor$
+func TestWithContextExtractor(t *testing.T) {
+	extractor := func(ctx context.Context) []slog.Attr { return nil }
+
+	conf := &config{contextExtractors: nil}
+	WithContextExtractor(extractor).applyTo(conf)
+	WithContextExtractor(extractor).applyTo(conf)
+
+	if len(conf.contextExtractors) != 2 {
+		t.Fatalf("len(conf.contextExtractors) %d != 2", len(conf.contextExtractors))
+	}
+
+	for i, got := range conf.contextExtractors {
+		if fmt.Sprintf("%p", got) != fmt.Sprintf("%p", extractor) {
+			t.Fatalf("conf.contextExtractors[%d] %p != extractor %p", i, got, extractor)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithHooks$
+func TestWithHooks(t *testing.T) {
+	hook := func(ctx context.Context, record *slog.Record) bool { return true }
+
+	conf := &config{hooks: nil}
+	WithHooks(hook, hook).applyTo(conf)
+	WithHooks(hook).applyTo(conf)
+
+	if len(conf.hooks) != 3 {
+		t.Fatalf("len(conf.hooks) %d != 3", len(conf.hooks))
+	}
+
+	for i, got := range conf.hooks {
+		if fmt.Sprintf("%p", got) != fmt.Sprintf("%p", hook) {
+			t.Fatalf("conf.hooks[%d] %p != hook %p", i, got, hook)
+		}
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithSource$
 func TestWithSource(t *testing.T) {
 	conf := &config{withSource: false}
@@ -347,6 +548,20 @@ func TestWithSource(t *testing.T) {
 	}
 }
 
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithStacktrace$
+func TestWithStacktrace(t *testing.T) {
+	conf := &config{withStacktrace: false}
+	WithStacktrace(slog.LevelWarn).applyTo(conf)
+
+	if !conf.withStacktrace {
+		t.Fatal("conf.withStacktrace is wrong")
+	}
+
+	if conf.stacktraceLevel != slog.LevelWarn {
+		t.Fatalf("conf.stacktraceLevel %+v != slog.LevelWarn", conf.stacktraceLevel)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestWithPID$
 func TestWithPID(t *testing.T) {
 	conf := &config{withPID: false}
@@ -366,3 +581,212 @@ func TestWithSyncTimer(t *testing.T) {
 		t.Fatal("conf.syncTimer is wrong")
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithDurability$
+func TestWithDurability(t *testing.T) {
+	conf := &config{durability: FsyncOnSync()}
+	WithDurability(FsyncInterval(time.Second)).applyTo(conf)
+
+	if conf.durability.mode != durabilityInterval || conf.durability.interval != time.Second {
+		t.Fatalf("conf.durability %+v is wrong", conf.durability)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithShutdownSignals$
+func TestWithShutdownSignals(t *testing.T) {
+	conf := &config{withShutdownSignals: false}
+	WithShutdownSignals(os.Interrupt).applyTo(conf)
+
+	if !conf.withShutdownSignals {
+		t.Fatal("conf.withShutdownSignals is wrong")
+	}
+
+	if len(conf.shutdownSignals) != 1 || conf.shutdownSignals[0] != os.Interrupt {
+		t.Fatalf("conf.shutdownSignals %+v is wrong", conf.shutdownSignals)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithRedaction$
+func TestWithRedaction(t *testing.T) {
+	conf := &config{redactor: nil}
+
+	patterns := []*regexp.Regexp{PatternJWT}
+	WithRedaction([]string{"Password"}, patterns, MaskSHA256).applyTo(conf)
+
+	if conf.redactor == nil {
+		t.Fatal("conf.redactor is nil")
+	}
+
+	if fmt.Sprintf("%v", conf.redactor.keys) != "[password]" {
+		t.Fatalf("conf.redactor.keys %v is wrong", conf.redactor.keys)
+	}
+
+	if len(conf.redactor.patterns) != 1 || conf.redactor.patterns[0] != PatternJWT {
+		t.Fatalf("conf.redactor.patterns %v is wrong", conf.redactor.patterns)
+	}
+
+	if conf.redactor.mask != MaskSHA256 {
+		t.Fatalf("conf.redactor.mask %d != MaskSHA256", conf.redactor.mask)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAsync$
+func TestWithAsync(t *testing.T) {
+	conf := &config{asyncMiddleware: nil}
+	WithAsync(writer.WithQueueSize(16)).applyTo(conf)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 256))
+	w := conf.asyncMiddleware(buffer)
+
+	aw, ok := w.(*writer.AsyncWriter)
+	if !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	defer aw.Close()
+
+	if _, err := aw.Write([]byte("async")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := aw.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.String() != "async" {
+		t.Fatalf("buffer.String() %s != async", buffer.String())
+	}
+}
+
+type testPrefixWriter struct {
+	writer io.Writer
+	prefix string
+}
+
+func (tpw *testPrefixWriter) Write(p []byte) (n int, err error) {
+	if _, err = tpw.writer.Write(append([]byte(tpw.prefix), p...)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithAsyncOrder$
+func TestWithAsyncOrder(t *testing.T) {
+	conf := newDefaultConfig()
+	WithAsync().applyTo(conf)
+	WithBuffer(1024).applyTo(conf)
+	WithBatch(16).applyTo(conf)
+
+	middlewares := conf.middlewares()
+	if len(middlewares) != 3 {
+		t.Fatalf("len(middlewares) %d != 3", len(middlewares))
+	}
+
+	var w io.Writer = io.Discard
+	for _, middleware := range middlewares {
+		w = middleware(w)
+	}
+
+	// The async writer should be the outermost one even if WithAsync is the first option.
+	aw, ok := w.(*writer.AsyncWriter)
+	if !ok {
+		t.Fatalf("writer type %T is wrong", w)
+	}
+
+	if err := aw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(conf.writerMiddlewares) != 2 {
+		t.Fatalf("len(conf.writerMiddlewares) %d != 2", len(conf.writerMiddlewares))
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithWriterMiddleware$
+func TestWithWriterMiddleware(t *testing.T) {
+	prefix := func(prefix string) func(io.Writer) io.Writer {
+		return func(w io.Writer) io.Writer {
+			return &testPrefixWriter{writer: w, prefix: prefix}
+		}
+	}
+
+	conf := &config{writerMiddlewares: nil}
+	WithWriterMiddleware(prefix("1"), prefix("2")).applyTo(conf)
+	WithBuffer(1024).applyTo(conf)
+	WithWriterMiddleware(prefix("3")).applyTo(conf)
+
+	if len(conf.writerMiddlewares) != 4 {
+		t.Fatalf("len(conf.writerMiddlewares) %d != 4", len(conf.writerMiddlewares))
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
+	logger := NewLogger(WithWriter(buffer), WithWriterMiddleware(prefix("1"), prefix("2")), WithBuffer(1024), WithWriterMiddleware(prefix("3")))
+
+	logger.Info("middleware")
+	if buffer.Len() != 0 {
+		t.Fatalf("buffer.Len() %d != 0", buffer.Len())
+	}
+
+	// The buffer writer is wrapped by a writer without Sync method, but it still should be synced.
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buffer.String(); !strings.HasPrefix(got, "123") || !strings.Contains(got, "middleware") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithWriterMiddlewareClose$
+func TestWithWriterMiddlewareClose(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), t.Name()+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	prefix := func(w io.Writer) io.Writer {
+		return &testPrefixWriter{writer: w, prefix: "1"}
+	}
+
+	logger := NewLogger(WithWriter(file), WithBuffer(1024), WithWriterMiddleware(prefix), WithBatch(16))
+	logger.Info("close")
+
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The buffer writer is wrapped by a writer without Close method, but it and the file still should be closed.
+	if _, err = file.Write([]byte("after close")); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("err %+v isn't os.ErrClosed", err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(data); !strings.HasPrefix(got, "1") || !strings.Contains(got, "close") {
+		t.Fatalf("got %s is wrong", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestWithOutputs$
+func TestWithOutputs(t *testing.T) {
+	conf := &config{outputs: nil}
+	WithOutputs(Output{Level: slog.LevelDebug}).applyTo(conf)
+	WithOutputs(Output{Level: slog.LevelInfo}, Output{Level: slog.LevelError}).applyTo(conf)
+
+	if len(conf.outputs) != 3 {
+		t.Fatalf("len(conf.outputs) %d != 3", len(conf.outputs))
+	}
+
+	levels := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelError}
+	for i, output := range conf.outputs {
+		if output.Level != levels[i] {
+			t.Fatalf("conf.outputs[%d].Level %v != %v", i, output.Level, levels[i])
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/FishGoddess/logit/handler"
+)
+
+// Output is an output of logger which has its own level, handler and writer.
+// Records will be handled by the handler and written to the writer if their levels reach the level of output.
+type Output struct {
+	// Level is the min level of records handled by this output.
+	// Notice that records are also filtered by the level of logger, see WithLevel.
+	Level slog.Level
+
+	// Handler is the name of handler used by this output, see handler.Register.
+	// The tape handler will be used if it's empty.
+	Handler string
+
+	// Writer is where this output writes logs to.
+	// Stdout will be used if both Writer and NewWriter are nil.
+	Writer io.Writer
+
+	// NewWriter creates the writer of this output when creating logger, and it's used only if Writer is nil.
+	// It's useful if you want to open a file lazily, and the error returned will be returned by NewLoggerGracefully.
+	NewWriter func() (io.Writer, error)
+
+	// Wrap wraps the writer in order, like writer.Buffer and writer.Batch.
+	Wrap []func(io.Writer) io.Writer
+}
+
+func (o *Output) handler() string {
+	if o.Handler == "" {
+		return handler.Tape
+	}
+
+	return o.Handler
+}
+
+func (o *Output) newWriter() (io.Writer, error) {
+	if o.Writer != nil {
+		return o.Writer, nil
+	}
+
+	if o.NewWriter != nil {
+		return o.NewWriter()
+	}
+
+	return os.Stdout, nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/handler"
+	"github.com/FishGoddess/logit/writer"
+)
+
+type testOutputWriter struct {
+	bytes.Buffer
+
+	synced bool
+	closed bool
+}
+
+func (tow *testOutputWriter) Sync() error {
+	tow.synced = true
+	return nil
+}
+
+func (tow *testOutputWriter) Close() error {
+	tow.closed = true
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestOutput$
+func TestOutput(t *testing.T) {
+	output := Output{}
+	if output.handler() != handler.Tape {
+		t.Fatalf("output.handler() %s != handler.Tape", output.handler())
+	}
+
+	w, err := output.newWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w != os.Stdout {
+		t.Fatalf("w %+v != os.Stdout", w)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	output = Output{
+		Handler: handler.Json,
+		Writer:  buffer,
+		NewWriter: func() (io.Writer, error) {
+			return nil, errors.New("new writer should not be called")
+		},
+	}
+
+	if output.handler() != handler.Json {
+		t.Fatalf("output.handler() %s != handler.Json", output.handler())
+	}
+
+	if w, err = output.newWriter(); err != nil {
+		t.Fatal(err)
+	}
+
+	if w != buffer {
+		t.Fatalf("w %+v != buffer", w)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerOutputs$
+func TestLoggerOutputs(t *testing.T) {
+	debugWriter := new(testOutputWriter)
+	infoWriter := new(testOutputWriter)
+	errorWriter := new(testOutputWriter)
+
+	wrapped := false
+	wrap := func(w io.Writer) io.Writer {
+		wrapped = true
+		return writer.Buffer(w, 1024)
+	}
+
+	logger := NewLogger(
+		WithDebugLevel(),
+		WithOutputs(Output{Level: slog.LevelDebug, Handler: handler.Text, Writer: debugWriter}),
+		WithOutputs(
+			Output{Level: slog.LevelInfo, Handler: handler.Json, Writer: infoWriter, Wrap: []func(io.Writer) io.Writer{wrap}},
+			Output{Level: slog.LevelError, NewWriter: func() (io.Writer, error) { return errorWriter, nil }},
+		),
+	)
+
+	if !wrapped {
+		t.Fatal("writer isn't wrapped")
+	}
+
+	logger = logger.With("key", "value")
+	logger.Debug("debug msg")
+	logger.Info("info msg")
+	logger.Error("error msg")
+
+	if got := strings.Count(debugWriter.String(), "\n"); got != 3 {
+		t.Fatalf("got %d != 3", got)
+	}
+
+	if !strings.Contains(debugWriter.String(), `level=DEBUG msg="debug msg" key=value`) {
+		t.Fatalf("debugWriter %s is wrong", debugWriter.String())
+	}
+
+	// Info writer is buffered so nothing is written before syncing.
+	if infoWriter.Len() != 0 {
+		t.Fatalf("infoWriter %s isn't empty", infoWriter.String())
+	}
+
+	if got := strings.Count(errorWriter.String(), "\n"); got != 1 {
+		t.Fatalf("got %d != 1", got)
+	}
+
+	if !strings.Contains(errorWriter.String(), "ERROR ¦ error msg ¦ key=value") {
+		t.Fatalf("errorWriter %s is wrong", errorWriter.String())
+	}
+
+	// The level of logger is checked before levels of outputs.
+	logger.SetLevel(slog.LevelWarn)
+	logger.Info("info msg after setting level")
+
+	if strings.Contains(debugWriter.String(), "after setting level") {
+		t.Fatalf("debugWriter %s is wrong", debugWriter.String())
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Count(infoWriter.String(), "\n"); got != 2 {
+		t.Fatalf("got %d != 2", got)
+	}
+
+	if !strings.Contains(infoWriter.String(), `"msg":"info msg","key":"value"`) {
+		t.Fatalf("infoWriter %s is wrong", infoWriter.String())
+	}
+
+	for i, w := range []*testOutputWriter{debugWriter, errorWriter} {
+		if !w.synced || !w.closed {
+			t.Fatalf("writer %d synced %+v closed %+v is wrong", i, w.synced, w.closed)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerOutputsLevelOverrides$
+func TestLoggerOutputsLevelOverrides(t *testing.T) {
+	debugWriter := new(testOutputWriter)
+	infoWriter := new(testOutputWriter)
+
+	logger := NewLogger(
+		WithInfoLevel(),
+		WithLevelOverrides(map[string]slog.Level{"db": slog.LevelDebug}),
+		WithOutputs(Output{Level: slog.LevelDebug, Writer: debugWriter}, Output{Level: slog.LevelInfo, Writer: infoWriter}),
+	)
+
+	logger.Debug("debug msg")
+	logger.Named("db").Debug("db debug msg")
+
+	if got := debugWriter.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, "db debug msg") {
+		t.Fatalf("got %s is wrong", got)
+	}
+
+	if infoWriter.Len() != 0 {
+		t.Fatalf("infoWriter %s isn't empty", infoWriter.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerOutputsError$
+func TestLoggerOutputsError(t *testing.T) {
+	w := new(testOutputWriter)
+
+	newWriter := func() (io.Writer, error) {
+		return nil, errors.New("new writer failed")
+	}
+
+	_, err := NewLoggerGracefully(WithOutputs(Output{Writer: w}, Output{NewWriter: newWriter}))
+	if err == nil {
+		t.Fatal("new logger should be failed")
+	}
+
+	if !w.closed {
+		t.Fatal("writer created should be closed after failing")
+	}
+
+	_, err = NewLoggerGracefully(WithOutputs(Output{Handler: "unknown"}))
+	if err == nil {
+		t.Fatal("new logger with unknown handler should be failed")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	keyPanic     = "panic"
+	keyGoroutine = "goroutine"
+
+	// recoverMsg is the msg of panics recovered by Go.
+	recoverMsg = "goroutine panicked"
+
+	// runtimePrefix is the prefix of functions in runtime package.
+	runtimePrefix = "runtime."
+)
+
+type recoverConfig struct {
+	ctx     context.Context
+	level   slog.Level
+	repanic bool
+	args    []any
+}
+
+// RecoverOption is a function for setting recover config.
+type RecoverOption func(conf *recoverConfig)
+
+func (ro RecoverOption) applyTo(conf *recoverConfig) {
+	ro(conf)
+}
+
+// WithRecoverLevel sets the level of logging panics to recover config.
+// The process will exit after logging if level is defaults.LevelFatal or higher, unless WithRepanic is set.
+func WithRecoverLevel(level slog.Level) RecoverOption {
+	return func(conf *recoverConfig) {
+		conf.level = level
+	}
+}
+
+// WithRecoverContext sets the context of logging panics to recover config.
+// Attrs in context will be carried, see WithContextAttrs and WithContextExtractor.
+func WithRecoverContext(ctx context.Context) RecoverOption {
+	return func(conf *recoverConfig) {
+		conf.ctx = ctx
+	}
+}
+
+// WithRecoverArgs sets args to recover config, and logs of panics will carry them.
+func WithRecoverArgs(args ...any) RecoverOption {
+	return func(conf *recoverConfig) {
+		conf.args = append(conf.args, args...)
+	}
+}
+
+// WithRepanic sets repanic=true to recover config.
+// The recovered value will be panicked again after logging and syncing.
+func WithRepanic() RecoverOption {
+	return func(conf *recoverConfig) {
+		conf.repanic = true
+	}
+}
+
+func newRecoverConfig(opts []RecoverOption) *recoverConfig {
+	conf := &recoverConfig{
+		ctx:     context.Background(),
+		level:   slog.LevelError,
+		repanic: false,
+	}
+
+	for _, opt := range opts {
+		opt.applyTo(conf)
+	}
+
+	if conf.ctx == nil {
+		conf.ctx = context.Background()
+	}
+
+	return conf
+}
+
+// afterPanic returns the stack starting from the frame which panicked.
+// Frames of runtime, like runtime.gopanic and runtime.panicmem, are skipped.
+// It returns the whole stack if runtime.gopanic isn't found.
+func (s stack) afterPanic() stack {
+	function := func(pc uintptr) string {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		return frame.Function
+	}
+
+	for i, pc := range s {
+		if function(pc) != "runtime.gopanic" {
+			continue
+		}
+
+		i++
+		for i < len(s) && strings.HasPrefix(function(s[i]), runtimePrefix) {
+			i++
+		}
+
+		return s[i:]
+	}
+
+	return s
+}
+
+// goroutineID returns the id of current goroutine parsed from the header of runtime.Stack like "goroutine 1 [running]".
+// It returns 0 if failed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 {
+		return 0
+	}
+
+	id, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
+
+// recoverPanic logs the panic value and syncs logger, and then repanics or exits if needed.
+func (l *Logger) recoverPanic(value any, msg string, opts []RecoverOption) {
+	conf := newRecoverConfig(opts)
+
+	if l.enabled(conf.ctx, conf.level) {
+		stack := captureStack(1).afterPanic()
+
+		var pc uintptr
+		if l.withSource && len(stack) > 0 {
+			pc = stack[0]
+		}
+
+		record := slog.NewRecord(defaults.CurrentTime(), conf.level, msg, pc)
+		l.addLoggerAttrs(conf.ctx, &record)
+		record.AddAttrs(slog.Any(keyPanic, value), slog.Uint64(keyGoroutine, goroutineID()))
+		record.AddAttrs(newAttrs(conf.args)...)
+		record.AddAttrs(slog.Any(keyStack, stack))
+
+		if err := l.handle(conf.ctx, record); err != nil {
+			defaults.HandleError("Logger.handler.Handle", err)
+		}
+	}
+
+	l.syncBeforeExiting()
+
+	if conf.repanic {
+		panic(value)
+	}
+
+	if conf.level >= defaults.LevelFatal {
+		defaults.Exit(1)
+	}
+}
+
+// Recover recovers a panic and logs it with msg, the panic value, the goroutine id and the stack.
+// It must be called directly by defer like "defer logger.Recover(msg)", or it can't recover panics.
+// The panic is logged in error level by default and the logger will be synced after logging.
+// The panic will be swallowed unless WithRepanic is set.
+func (l *Logger) Recover(msg string, opts ...RecoverOption) {
+	if value := recover(); value != nil {
+		l.recoverPanic(value, msg, opts)
+	}
+}
+
+// Go runs fn in a new goroutine and recovers the panic of fn with logger.
+// The default logger will be used if logger is nil.
+// See Logger.Recover.
+func Go(logger *Logger, fn func(), opts ...RecoverOption) {
+	if logger == nil {
+		logger = Default()
+	}
+
+	go func() {
+		defer logger.Recover(recoverMsg, opts...)
+		fn()
+	}()
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+type testRecoverLog struct {
+	Level     string   `json:"level"`
+	Msg       string   `json:"msg"`
+	Panic     any      `json:"panic"`
+	Goroutine uint64   `json:"goroutine"`
+	Stack     []string `json:"stack"`
+	TraceID   string   `json:"trace_id"`
+	Key       string   `json:"key"`
+
+	Source struct {
+		File string `json:"file"`
+	} `json:"source"`
+}
+
+func parseTestRecoverLog(t *testing.T, buffer *bytes.Buffer) testRecoverLog {
+	var log testRecoverLog
+	if err := json.Unmarshal(buffer.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	return log
+}
+
+func panicInTest(value any) {
+	panic(value)
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerRecover$
+func TestLoggerRecover(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithWriter(buffer), WithBuffer(4096), WithJsonHandler(), WithSource())
+
+	ctx := WithContextAttrs(context.Background(), "trace_id", "123")
+
+	func() {
+		defer logger.Recover("recovered", WithRecoverContext(ctx), WithRecoverArgs("key", "value"))
+		panicInTest("oops")
+	}()
+
+	// The logger should be synced after recovering.
+	log := parseTestRecoverLog(t, buffer)
+	if log.Level != "ERROR" || log.Msg != "recovered" || log.Panic != "oops" {
+		t.Fatalf("log %+v is wrong", log)
+	}
+
+	if log.Goroutine == 0 {
+		t.Fatal("log.Goroutine is 0")
+	}
+
+	if log.TraceID != "123" || log.Key != "value" {
+		t.Fatalf("log %+v is wrong", log)
+	}
+
+	if filepath.Base(log.Source.File) != "recover_test.go" {
+		t.Fatalf("log.Source.File %s is wrong", log.Source.File)
+	}
+
+	if len(log.Stack) < 2 {
+		t.Fatalf("len(log.Stack) %d < 2", len(log.Stack))
+	}
+
+	if !strings.HasPrefix(log.Stack[0], "github.com/FishGoddess/logit.panicInTest ") {
+		t.Fatalf("log.Stack[0] %s is wrong", log.Stack[0])
+	}
+
+	if !strings.HasPrefix(log.Stack[1], "github.com/FishGoddess/logit.TestLoggerRecover.") {
+		t.Fatalf("log.Stack[1] %s is wrong", log.Stack[1])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerRecoverRuntimeError$
+func TestLoggerRecoverRuntimeError(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler())
+
+	func() {
+		defer logger.Recover("recovered")
+
+		var m map[string]int
+		m["key"] = 1
+	}()
+
+	log := parseTestRecoverLog(t, buffer)
+
+	panicValue, ok := log.Panic.(map[string]any)
+	if !ok || !strings.Contains(panicValue["msg"].(string), "nil map") {
+		t.Fatalf("log.Panic %+v is wrong", log.Panic)
+	}
+
+	if !strings.HasPrefix(log.Stack[0], "github.com/FishGoddess/logit.TestLoggerRecoverRuntimeError.") {
+		t.Fatalf("log.Stack[0] %s is wrong", log.Stack[0])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerRecoverRepanic$
+func TestLoggerRecoverRepanic(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler())
+
+	defer func() {
+		if value := recover(); value != "oops" {
+			t.Fatalf("value %+v != oops", value)
+		}
+
+		if log := parseTestRecoverLog(t, buffer); log.Panic != "oops" {
+			t.Fatalf("log.Panic %+v != oops", log.Panic)
+		}
+	}()
+
+	defer logger.Recover("recovered", WithRepanic())
+	panicInTest("oops")
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerRecoverFatal$
+func TestLoggerRecoverFatal(t *testing.T) {
+	exit := defaults.Exit
+	defer func() {
+		defaults.Exit = exit
+	}()
+
+	exitCode := 0
+	defaults.Exit = func(code int) {
+		exitCode = code
+	}
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithWriter(buffer), WithJsonHandler())
+
+	func() {
+		defer logger.Recover("recovered", WithRecoverLevel(defaults.LevelFatal))
+		panicInTest("oops")
+	}()
+
+	if exitCode != 1 {
+		t.Fatalf("exitCode %d != 1", exitCode)
+	}
+
+	if log := parseTestRecoverLog(t, buffer); log.Level != "FATAL" {
+		t.Fatalf("log.Level %s != FATAL", log.Level)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestLoggerRecoverNoPanic$
+func TestLoggerRecoverNoPanic(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	logger := NewLogger(WithWriter(buffer))
+
+	func() {
+		defer logger.Recover("recovered")
+	}()
+
+	if buffer.Len() != 0 {
+		t.Fatalf("buffer %s isn't empty", buffer.String())
+	}
+}
+
+type notifyWriter struct {
+	buffer  *bytes.Buffer
+	written chan struct{}
+}
+
+func (nw *notifyWriter) Write(p []byte) (n int, err error) {
+	n, err = nw.buffer.Write(p)
+	nw.written <- struct{}{}
+	return n, err
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestGo$
+func TestGo(t *testing.T) {
+	writer := &notifyWriter{
+		buffer:  bytes.NewBuffer(make([]byte, 0, 4096)),
+		written: make(chan struct{}, 1),
+	}
+
+	logger := NewLogger(WithWriter(writer), WithJsonHandler())
+
+	Go(logger, func() {
+		panicInTest("oops in goroutine")
+	})
+
+	<-writer.written
+
+	log := parseTestRecoverLog(t, writer.buffer)
+	if log.Msg != recoverMsg || log.Panic != "oops in goroutine" {
+		t.Fatalf("log %+v is wrong", log)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestRecover$
+func TestRecover(t *testing.T) {
+	defaultLogger := Default()
+	defer SetDefault(defaultLogger)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+	SetDefault(NewLogger(WithWriter(buffer), WithJsonHandler()))
+
+	func() {
+		defer Recover("recovered by default")
+		panicInTest("oops")
+	}()
+
+	log := parseTestRecoverLog(t, buffer)
+	if log.Msg != "recovered by default" || log.Panic != "oops" {
+		t.Fatalf("log %+v is wrong", log)
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	redactedMask      = "******"
+	keepLastCount     = 4
+	fingerprintPrefix = "sha256:"
+	fingerprintSize   = 8
+)
+
+// RedactionMask is the style of masking sensitive values.
+type RedactionMask int
+
+const (
+	// MaskFull replaces the whole value with a fixed mask, so even the length of value is hidden.
+	MaskFull RedactionMask = iota
+
+	// MaskKeepLast4 replaces the value with a fixed mask but keeps the last 4 characters.
+	// Values with no more than 4 characters will be masked fully.
+	MaskKeepLast4
+
+	// MaskSHA256 replaces the value with a fingerprint of its sha256 sum like "sha256:0123456789abcdef".
+	// It's useful to tell whether two values are the same without leaking them.
+	MaskSHA256
+)
+
+var (
+	// PatternJWT matches json web tokens.
+	PatternJWT = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+	// PatternCreditCard matches credit card numbers which have 13 to 19 digits separated by spaces or dashes.
+	// Notice that it also matches other long numbers in strings, like timestamps in milliseconds.
+	PatternCreditCard = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+	// PatternBearerToken matches bearer tokens in authorization headers, like "Bearer xxx".
+	PatternBearerToken = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`)
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// redactor redacts values of sensitive keys and values matching patterns.
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	mask     RedactionMask
+
+	// keyTypes caches whether values of a type may have sensitive keys after being marshaled to json.
+	keyTypes sync.Map
+}
+
+func newRedactor(keys []string, patterns []*regexp.Regexp, mask RedactionMask) *redactor {
+	lowerKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lowerKeys = append(lowerKeys, strings.ToLower(key))
+	}
+
+	r := &redactor{
+		keys:     lowerKeys,
+		patterns: patterns,
+		mask:     mask,
+	}
+
+	return r
+}
+
+// sensitive reports whether the key matches one of the sensitive keys.
+func (r *redactor) sensitive(key string) bool {
+	if len(r.keys) <= 0 || key == "" {
+		return false
+	}
+
+	key = strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sensitiveGroups reports whether one of groups is a sensitive key.
+func (r *redactor) sensitiveGroups(groups []string) bool {
+	for _, group := range groups {
+		if r.sensitive(group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maskString masks the value in the mask style.
+func (r *redactor) maskString(value string) string {
+	switch r.mask {
+	case MaskKeepLast4:
+		runes := []rune(value)
+		if len(runes) <= keepLastCount {
+			return redactedMask
+		}
+
+		return redactedMask + string(runes[len(runes)-keepLastCount:])
+	case MaskSHA256:
+		sum := sha256.Sum256([]byte(value))
+		return fingerprintPrefix + hex.EncodeToString(sum[:fingerprintSize])
+	default:
+		return redactedMask
+	}
+}
+
+// redactString masks all parts of value matching patterns and reports whether the value is redacted.
+func (r *redactor) redactString(value string) (string, bool) {
+	redacted := false
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(value) {
+			value = pattern.ReplaceAllStringFunc(value, r.maskString)
+			redacted = true
+		}
+	}
+
+	return value, redacted
+}
+
+// redactJson redacts a value decoded from json and reports whether the value is redacted.
+func (r *redactor) redactJson(value any) (any, bool) {
+	redacted := false
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if r.sensitive(key) {
+				v[key] = r.maskString(jsonString(item))
+				redacted = true
+				continue
+			}
+
+			if newItem, ok := r.redactJson(item); ok {
+				v[key] = newItem
+				redacted = true
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if newItem, ok := r.redactJson(item); ok {
+				v[i] = newItem
+				redacted = true
+			}
+		}
+	case string:
+		return r.redactString(v)
+	}
+
+	return value, redacted
+}
+
+// typeHasSensitiveKeys reports whether values of typ may have sensitive keys after being marshaled to json.
+// Types with dynamic keys, like maps, interfaces and json marshalers, may always have sensitive keys.
+func (r *redactor) typeHasSensitiveKeys(typ reflect.Type, visited map[reflect.Type]struct{}) bool {
+	if _, ok := visited[typ]; ok {
+		return false
+	}
+
+	visited[typ] = struct{}{}
+
+	if typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return r.typeHasSensitiveKeys(typ.Elem(), visited)
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() && !field.Anonymous {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+
+			// Fields of embedded structs are inlined in json.
+			if name == "" && field.Anonymous {
+				if r.typeHasSensitiveKeys(field.Type, visited) {
+					return true
+				}
+
+				continue
+			}
+
+			if name == "" {
+				name = field.Name
+			}
+
+			if r.sensitive(name) || r.typeHasSensitiveKeys(field.Type, visited) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
+// mayHaveSensitiveKeys reports whether value may have sensitive keys after being marshaled to json.
+func (r *redactor) mayHaveSensitiveKeys(value any) bool {
+	if len(r.keys) <= 0 || value == nil {
+		return false
+	}
+
+	typ := reflect.TypeOf(value)
+	if cached, ok := r.keyTypes.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	has := r.typeHasSensitiveKeys(typ, make(map[reflect.Type]struct{}))
+	r.keyTypes.Store(typ, has)
+	return has
+}
+
+// matchPatterns reports whether data matches one of patterns.
+func (r *redactor) matchPatterns(data []byte) bool {
+	for _, pattern := range r.patterns {
+		if pattern.Match(data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactAny redacts a value which will be marshaled to json or formatted to a string by handlers.
+// The value is decoded and redacted only if it may have sensitive keys or its json matches patterns.
+// The redacted value will be a json.RawMessage so handlers still output it as json.
+func (r *redactor) redactAny(value any) (any, bool) {
+	if err, ok := value.(error); ok {
+		return r.redactString(err.Error())
+	}
+
+	// Json handlers marshal stringers to json instead of calling String, so they may have sensitive keys too.
+	if stringer, ok := value.(fmt.Stringer); ok && !r.mayHaveSensitiveKeys(value) {
+		return r.redactString(stringer.String())
+	}
+
+	hasKeys := r.mayHaveSensitiveKeys(value)
+	if !hasKeys && len(r.patterns) <= 0 {
+		return value, false
+	}
+
+	marshaled, err := json.Marshal(value)
+	if err != nil {
+		return value, false
+	}
+
+	if !hasKeys && !r.matchPatterns(marshaled) {
+		return value, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(marshaled))
+	decoder.UseNumber()
+
+	var decoded any
+	if err = decoder.Decode(&decoded); err != nil {
+		return value, false
+	}
+
+	decoded, redacted := r.redactJson(decoded)
+	if !redacted {
+		return value, false
+	}
+
+	if marshaled, err = json.Marshal(decoded); err != nil {
+		return value, false
+	}
+
+	return json.RawMessage(marshaled), true
+}
+
+// replaceAttr redacts the attr if its key is sensitive or its value matches patterns.
+func (r *redactor) replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) <= 0 {
+		switch attr.Key {
+		case slog.TimeKey, slog.LevelKey, slog.SourceKey:
+			return attr
+		}
+	}
+
+	attr.Value = attr.Value.Resolve()
+
+	if r.sensitive(attr.Key) || r.sensitiveGroups(groups) {
+		attr.Value = slog.StringValue(r.maskString(valueString(attr.Value)))
+		return attr
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		// Values resolved from log valuers may be groups, so redact attrs in groups one by one.
+		if attr.Key != "" {
+			groups = append(slices.Clip(groups), attr.Key)
+		}
+
+		groupAttrs := attr.Value.Group()
+		redactedAttrs := make([]slog.Attr, 0, len(groupAttrs))
+
+		for _, groupAttr := range groupAttrs {
+			redactedAttrs = append(redactedAttrs, r.replaceAttr(groups, groupAttr))
+		}
+
+		attr.Value = slog.GroupValue(redactedAttrs...)
+	case slog.KindString:
+		if value, ok := r.redactString(attr.Value.String()); ok {
+			attr.Value = slog.StringValue(value)
+		}
+	case slog.KindAny:
+		if value, ok := r.redactAny(attr.Value.Any()); ok {
+			attr.Value = slog.AnyValue(value)
+		}
+	}
+
+	return attr
+}
+
+// valueString returns the string of value which will be masked.
+func valueString(value slog.Value) string {
+	if value.Kind() == slog.KindAny {
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+	}
+
+	return value.String()
+}
+
+// jsonString returns the string of a value decoded from json.
+func jsonString(value any) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	marshaled, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(marshaled)
+}
+
+// composeReplaceAttr returns a function calling first and then second.
+// Attrs removed by first won't be passed to second.
+func composeReplaceAttr(first, second func(groups []string, attr slog.Attr) slog.Attr) func(groups []string, attr slog.Attr) slog.Attr {
+	if first == nil {
+		return second
+	}
+
+	if second == nil {
+		return first
+	}
+
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		attr = first(groups, attr)
+		if attr.Key == "" {
+			return attr
+		}
+
+		return second(groups, attr)
+	}
+}
@@ -258,6 +258,12 @@ func (f *File) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
+// Flush does nothing since file doesn't buffer data and all data have been written to the os.
+// Use Sync if you want data to be durable.
+func (f *File) Flush() error {
+	return nil
+}
+
 // Sync syncs data to the underlying io device.
 func (f *File) Sync() error {
 	f.lock.Lock()
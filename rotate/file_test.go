@@ -197,3 +197,37 @@ func TestFileRotate(t *testing.T) {
 		t.Fatalf("string(read) %s != '!!!bursttest'", read)
 	}
 }
+
+// go test -v -cover -count=1 -run=^TestFileFlushAndSync$
+func TestFileFlushAndSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	f, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	data := []byte("test")
+	if _, err = f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(read) != string(data) {
+		t.Fatalf("string(read) %s != string(data) %s", read, data)
+	}
+}
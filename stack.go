@@ -0,0 +1,83 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	keyStack = "stack"
+
+	// maxStackDepth is the max depth of frames in a stack.
+	maxStackDepth = 64
+)
+
+// stack is the program counters of a goroutine stack.
+// Frames will be resolved only if the stack is handled, so capturing a stack is cheap.
+type stack []uintptr
+
+// captureStack captures the stack of current goroutine and skips some frames.
+// The skip is the same as runtime.Callers and the frame of captureStack is skipped too.
+func captureStack(skip int) stack {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+1, pcs[:])
+
+	return append(stack(nil), pcs[:n]...)
+}
+
+// from returns the stack starting from the frame of pc.
+// It returns the whole stack if pc isn't found.
+func (s stack) from(pc uintptr) stack {
+	for i, framePC := range s {
+		if framePC == pc {
+			return s[i:]
+		}
+	}
+
+	return s
+}
+
+// Frames returns all frames in stack, and every frame is like "function file:line".
+func (s stack) Frames() []string {
+	frames := runtime.CallersFrames(s)
+	result := make([]string, 0, len(s))
+
+	for {
+		frame, more := frames.Next()
+		if frame.PC != 0 {
+			result = append(result, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+// String returns all frames in stack joined with line breaks.
+func (s stack) String() string {
+	return strings.Join(s.Frames(), "\n")
+}
+
+// MarshalJSON returns all frames in stack as a json array.
+func (s stack) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Frames())
+}
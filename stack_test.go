@@ -0,0 +1,61 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestCaptureStack$
+func TestCaptureStack(t *testing.T) {
+	stack := captureStack(1)
+	if len(stack) <= 0 {
+		t.Fatalf("len(stack) %d <= 0", len(stack))
+	}
+
+	frames := stack.Frames()
+	if len(frames) <= 0 {
+		t.Fatalf("len(frames) %d <= 0", len(frames))
+	}
+
+	if !strings.HasPrefix(frames[0], "github.com/FishGoddess/logit.TestCaptureStack ") {
+		t.Fatalf("frames[0] %s is wrong", frames[0])
+	}
+
+	if !strings.Contains(frames[0], "stack_test.go:") {
+		t.Fatalf("frames[0] %s is wrong", frames[0])
+	}
+
+	str := stack.String()
+	if str != strings.Join(frames, "\n") {
+		t.Fatalf("str %s is wrong", str)
+	}
+
+	marshaled, err := json.Marshal(stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	if err = json.Unmarshal(marshaled, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(frames) {
+		t.Fatalf("len(got) %d != len(frames) %d", len(got), len(frames))
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	defaultQueueSize    = 1024
+	defaultDrainTimeout = 5 * time.Second
+
+	// dropReportInterval is the interval of reporting dropped count to defaults.HandleError.
+	dropReportInterval = time.Second
+)
+
+var (
+	// ErrAsyncClosed is the error returned when writing to a closed async writer.
+	ErrAsyncClosed = errors.New("logit: async writer has been closed")
+
+	// ErrAsyncTimeout is the error returned when the queue of async writer isn't drained before deadline.
+	ErrAsyncTimeout = errors.New("logit: async writer drains timeout")
+)
+
+// OverflowPolicy is what an async writer does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the writing goroutine until the queue has space, so no logs will be dropped.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest drops the data being written.
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest data in queue to make space for the data being written.
+	OverflowDropOldest
+
+	// OverflowDropBelowLevel drops logs below the drop level and blocks others.
+	// Writers don't know the levels of data, so logs are dropped by loggers before handling, see AsyncWriter.Admit.
+	OverflowDropBelowLevel
+)
+
+type asyncConfig struct {
+	queueSize    uint64
+	policy       OverflowPolicy
+	dropLevel    slog.Level
+	drainTimeout time.Duration
+}
+
+// AsyncOption is a function for setting async config.
+type AsyncOption func(conf *asyncConfig)
+
+func (ao AsyncOption) applyTo(conf *asyncConfig) {
+	ao(conf)
+}
+
+// WithQueueSize sets the max count of data in queue to async config.
+func WithQueueSize(queueSize uint64) AsyncOption {
+	return func(conf *asyncConfig) {
+		conf.queueSize = queueSize
+	}
+}
+
+// WithOverflowPolicy sets the overflow policy to async config.
+// The default policy is OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) AsyncOption {
+	return func(conf *asyncConfig) {
+		conf.policy = policy
+	}
+}
+
+// WithDropLevel sets the drop level to async config.
+// Logs below this level will be dropped when the queue is full if the policy is OverflowDropBelowLevel.
+func WithDropLevel(level slog.Level) AsyncOption {
+	return func(conf *asyncConfig) {
+		conf.dropLevel = level
+	}
+}
+
+// WithDrainTimeout sets the timeout of draining queue in Sync and Close to async config.
+func WithDrainTimeout(timeout time.Duration) AsyncOption {
+	return func(conf *asyncConfig) {
+		conf.drainTimeout = timeout
+	}
+}
+
+func newAsyncConfig(opts []AsyncOption) *asyncConfig {
+	conf := &asyncConfig{
+		queueSize:    defaultQueueSize,
+		policy:       OverflowBlock,
+		dropLevel:    slog.LevelWarn,
+		drainTimeout: defaultDrainTimeout,
+	}
+
+	for _, opt := range opts {
+		opt.applyTo(conf)
+	}
+
+	if conf.queueSize < 1 {
+		conf.queueSize = 1
+	}
+
+	return conf
+}
+
+// asyncRequest is a request of flushing or syncing underlying writer after draining queue.
+type asyncRequest struct {
+	fsync bool
+	reply chan error
+}
+
+func (ar asyncRequest) handle(writer io.Writer) error {
+	if ar.fsync {
+		return syncWriter(writer)
+	}
+
+	return flushWriter(writer)
+}
+
+// AsyncWriter is a writer writing data to underlying writer in a background goroutine.
+// Data will be put in a bounded queue, so a slow underlying writer won't block the writing goroutine unless the queue is full.
+type AsyncWriter struct {
+	// writer is the underlying writer to write data.
+	writer io.Writer
+
+	conf *asyncConfig
+
+	// queue is the bounded queue of data waiting to be written.
+	queue chan []byte
+
+	// requests receives flush and sync requests, and the flusher drains queue and flushes or syncs writer before replying.
+	requests chan asyncRequest
+
+	// closing is closed when closing, and done is closed after the flusher exits.
+	closing   chan struct{}
+	done      chan struct{}
+	closed    atomic.Bool
+	closeOnce sync.Once
+	closeErr  error
+
+	// writing is the count of writes in flight, and the final drain waits for them so no data will be lost after closing.
+	writing atomic.Int64
+
+	dropped  atomic.Uint64
+	reported atomic.Uint64
+}
+
+// Async returns a new async writer of writer and starts its flusher.
+// Remember closing the async writer, or the flusher goroutine won't exit.
+func Async(writer io.Writer, opts ...AsyncOption) *AsyncWriter {
+	if aw, ok := writer.(*AsyncWriter); ok {
+		return aw
+	}
+
+	conf := newAsyncConfig(opts)
+
+	aw := &AsyncWriter{
+		writer:   writer,
+		conf:     conf,
+		queue:    make(chan []byte, conf.queueSize),
+		requests: make(chan asyncRequest),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go aw.runFlusher()
+	return aw
+}
+
+// Dropped returns the count of logs dropped.
+func (aw *AsyncWriter) Dropped() uint64 {
+	return aw.dropped.Load()
+}
+
+func (aw *AsyncWriter) full() bool {
+	return len(aw.queue) >= cap(aw.queue)
+}
+
+// Admit reports whether a log in level should be written.
+// It returns false and counts a drop if the policy is OverflowDropBelowLevel, the queue is full and level is below the drop level.
+// Loggers call it before handling records, so logs are dropped without formatting.
+func (aw *AsyncWriter) Admit(level slog.Level) bool {
+	if aw.conf.policy != OverflowDropBelowLevel || level >= aw.conf.dropLevel || !aw.full() {
+		return true
+	}
+
+	aw.dropped.Add(1)
+	return false
+}
+
+// Write puts a copy of p to queue and handles overflow in policy.
+// It returns len(p) even if p is dropped, so handlers won't treat drops as errors.
+func (aw *AsyncWriter) Write(p []byte) (n int, err error) {
+	// Count the write before checking closed, so the final drain either waits for it or it sees closed.
+	aw.writing.Add(1)
+	defer aw.writing.Add(-1)
+
+	if aw.closed.Load() {
+		return 0, ErrAsyncClosed
+	}
+
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	switch aw.conf.policy {
+	case OverflowDropNewest:
+		select {
+		case aw.queue <- data:
+		default:
+			aw.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case aw.queue <- data:
+				return len(p), nil
+			default:
+			}
+
+			select {
+			case <-aw.queue:
+				aw.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case aw.queue <- data:
+		case <-aw.done:
+			return 0, ErrAsyncClosed
+		}
+	}
+
+	return len(p), nil
+}
+
+func (aw *AsyncWriter) write(data []byte) {
+	if _, err := aw.writer.Write(data); err != nil {
+		defaults.HandleError("writer.AsyncWriter.Write", err)
+	}
+}
+
+// drain writes all data in queue to underlying writer.
+func (aw *AsyncWriter) drain() {
+	for {
+		select {
+		case data := <-aw.queue:
+			aw.write(data)
+		default:
+			return
+		}
+	}
+}
+
+// reportDropped reports the count of logs dropped since last report to defaults.HandleError.
+func (aw *AsyncWriter) reportDropped() {
+	dropped := aw.dropped.Load()
+	reported := aw.reported.Swap(dropped)
+
+	if dropped > reported {
+		defaults.HandleError("writer.AsyncWriter", fmt.Errorf("logit: async writer dropped %d logs", dropped-reported))
+	}
+}
+
+// waitWriting waits for writes in flight and keeps writing data in queue since they may be blocked by the full queue.
+func (aw *AsyncWriter) waitWriting() {
+	for aw.writing.Load() > 0 {
+		select {
+		case data := <-aw.queue:
+			aw.write(data)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (aw *AsyncWriter) close() error {
+	// Writes starting after closed will fail, so no data will be put to queue after waiting.
+	aw.waitWriting()
+	aw.drain()
+	aw.reportDropped()
+
+	if err := flushWriter(aw.writer); err != nil {
+		return err
+	}
+
+	if closer, ok := aw.writer.(io.Closer); ok && notStdoutAndStderr(aw.writer) {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+func (aw *AsyncWriter) runFlusher() {
+	defer close(aw.done)
+
+	ticker := time.NewTicker(dropReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data := <-aw.queue:
+			aw.write(data)
+		case request := <-aw.requests:
+			aw.drain()
+			aw.reportDropped()
+			request.reply <- request.handle(aw.writer)
+		case <-ticker.C:
+			aw.reportDropped()
+		case <-aw.closing:
+			aw.closeErr = aw.close()
+			return
+		}
+	}
+}
+
+// request sends a request to the flusher and waits for its reply in drain timeout.
+func (aw *AsyncWriter) request(fsync bool) error {
+	timer := time.NewTimer(aw.conf.drainTimeout)
+	defer timer.Stop()
+
+	request := asyncRequest{fsync: fsync, reply: make(chan error, 1)}
+
+	select {
+	case aw.requests <- request:
+	case <-aw.done:
+		return nil
+	case <-timer.C:
+		return ErrAsyncTimeout
+	}
+
+	select {
+	case err := <-request.reply:
+		return err
+	case <-timer.C:
+		return ErrAsyncTimeout
+	}
+}
+
+// Flush drains queue and flushes underlying writer if it's a Flusher.
+// It returns ErrAsyncTimeout if the queue isn't drained in drain timeout.
+func (aw *AsyncWriter) Flush() error {
+	return aw.request(false)
+}
+
+// Sync drains queue and syncs underlying writer if it's a Syncer, so data will be durable after syncing.
+// It returns ErrAsyncTimeout if the queue isn't drained in drain timeout.
+func (aw *AsyncWriter) Sync() error {
+	return aw.request(true)
+}
+
+// Close drains queue, flushes and closes underlying writer if writer implements io.Closer.
+// It returns ErrAsyncTimeout if the queue isn't drained in drain timeout, and the flusher will keep draining in background.
+func (aw *AsyncWriter) Close() error {
+	aw.closeOnce.Do(func() {
+		aw.closed.Store(true)
+		close(aw.closing)
+	})
+
+	timer := time.NewTimer(aw.conf.drainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-aw.done:
+		return aw.closeErr
+	case <-timer.C:
+		return ErrAsyncTimeout
+	}
+}
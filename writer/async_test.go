@@ -0,0 +1,379 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+type blockWriter struct {
+	buffer  bytes.Buffer
+	started chan struct{}
+	unblock chan struct{}
+
+	lock sync.Mutex
+}
+
+func newBlockWriter() *blockWriter {
+	bw := &blockWriter{
+		started: make(chan struct{}, 1),
+		unblock: make(chan struct{}),
+	}
+
+	return bw
+}
+
+func (bw *blockWriter) Write(p []byte) (n int, err error) {
+	select {
+	case bw.started <- struct{}{}:
+	default:
+	}
+
+	<-bw.unblock
+
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	return bw.buffer.Write(p)
+}
+
+func (bw *blockWriter) String() string {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	return bw.buffer.String()
+}
+
+// fill writes "1" which blocks the flusher and then fills the queue with "2" and "3".
+func (bw *blockWriter) fill(t *testing.T, writer *AsyncWriter) {
+	writer.Write([]byte("1"))
+	<-bw.started
+
+	writer.Write([]byte("2"))
+	writer.Write([]byte("3"))
+
+	if !writer.full() {
+		t.Fatal("writer should be full")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsync$
+func TestAsync(t *testing.T) {
+	writer := Async(os.Stdout)
+	defer writer.Close()
+
+	if writer.conf.queueSize != defaultQueueSize {
+		t.Fatalf("writer.conf.queueSize %d != defaultQueueSize %d", writer.conf.queueSize, defaultQueueSize)
+	}
+
+	if writer.conf.policy != OverflowBlock {
+		t.Fatalf("writer.conf.policy %d != OverflowBlock", writer.conf.policy)
+	}
+
+	newWriter := Async(writer, WithQueueSize(16))
+	if newWriter != writer {
+		t.Fatal("newWriter is wrong")
+	}
+
+	writer = Async(os.Stdout, WithQueueSize(0), WithOverflowPolicy(OverflowDropOldest), WithDropLevel(slog.LevelError), WithDrainTimeout(time.Second))
+	defer writer.Close()
+
+	if cap(writer.queue) != 1 {
+		t.Fatalf("cap(writer.queue) %d != 1", cap(writer.queue))
+	}
+
+	if writer.conf.policy != OverflowDropOldest || writer.conf.dropLevel != slog.LevelError || writer.conf.drainTimeout != time.Second {
+		t.Fatalf("writer.conf %+v is wrong", writer.conf)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriter$
+func TestAsyncWriter(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+
+	writer := Async(buffer)
+	defer writer.Close()
+
+	data := []byte("abc")
+	writer.Write(data)
+	data[0] = 'x'
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.String() != "abc" {
+		t.Fatalf("writing abc but found %s in buffer", buffer.String())
+	}
+
+	writer.Write([]byte("123"))
+	writer.Write([]byte(".!?"))
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.String() != "abc123.!?" {
+		t.Fatalf("writing abc123.!? but found %s in buffer", buffer.String())
+	}
+
+	if _, err := writer.Write([]byte("+-*/")); err != ErrAsyncClosed {
+		t.Fatalf("err %+v != ErrAsyncClosed", err)
+	}
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriterOverflow$
+func TestAsyncWriterOverflow(t *testing.T) {
+	testCases := []struct {
+		policy  OverflowPolicy
+		want    string
+		dropped uint64
+	}{
+		{policy: OverflowDropNewest, want: "123", dropped: 1},
+		{policy: OverflowDropOldest, want: "134", dropped: 1},
+		{policy: OverflowDropBelowLevel, want: "1234", dropped: 0},
+		{policy: OverflowBlock, want: "1234", dropped: 0},
+	}
+
+	for _, testCase := range testCases {
+		bw := newBlockWriter()
+		writer := Async(bw, WithQueueSize(2), WithOverflowPolicy(testCase.policy))
+		bw.fill(t, writer)
+
+		written := make(chan struct{})
+		go func() {
+			writer.Write([]byte("4"))
+			close(written)
+		}()
+
+		blocking := testCase.policy == OverflowBlock || testCase.policy == OverflowDropBelowLevel
+		if blocking {
+			select {
+			case <-written:
+				t.Fatalf("policy %d: writing should be blocked", testCase.policy)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			close(bw.unblock)
+			<-written
+		} else {
+			<-written
+			close(bw.unblock)
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := bw.String(); got != testCase.want {
+			t.Fatalf("policy %d: got %s != want %s", testCase.policy, got, testCase.want)
+		}
+
+		if writer.Dropped() != testCase.dropped {
+			t.Fatalf("policy %d: writer.Dropped() %d != %d", testCase.policy, writer.Dropped(), testCase.dropped)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriterAdmit$
+func TestAsyncWriterAdmit(t *testing.T) {
+	bw := newBlockWriter()
+	writer := Async(bw, WithQueueSize(2), WithOverflowPolicy(OverflowDropBelowLevel), WithDropLevel(slog.LevelWarn))
+
+	if !writer.Admit(slog.LevelDebug) {
+		t.Fatal("debug should be admitted if the queue isn't full")
+	}
+
+	bw.fill(t, writer)
+
+	if writer.Admit(slog.LevelInfo) {
+		t.Fatal("info shouldn't be admitted if the queue is full")
+	}
+
+	if !writer.Admit(slog.LevelWarn) || !writer.Admit(slog.LevelError) {
+		t.Fatal("warn and error should be admitted")
+	}
+
+	if writer.Dropped() != 1 {
+		t.Fatalf("writer.Dropped() %d != 1", writer.Dropped())
+	}
+
+	close(bw.unblock)
+	writer.Close()
+
+	writer = Async(os.Stdout, WithQueueSize(1))
+	defer writer.Close()
+
+	if !writer.Admit(slog.LevelDebug) {
+		t.Fatal("all levels should be admitted if the policy isn't OverflowDropBelowLevel")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriterReportDropped$
+func TestAsyncWriterReportDropped(t *testing.T) {
+	handleError := defaults.HandleError
+	defer func() {
+		defaults.HandleError = handleError
+	}()
+
+	var lock sync.Mutex
+	var errs []string
+	defaults.HandleError = func(label string, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		errs = append(errs, label+": "+err.Error())
+	}
+
+	bw := newBlockWriter()
+	writer := Async(bw, WithQueueSize(2), WithOverflowPolicy(OverflowDropNewest))
+	bw.fill(t, writer)
+
+	writer.Write([]byte("4"))
+	writer.Write([]byte("5"))
+
+	close(bw.unblock)
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	writer.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	want := "writer.AsyncWriter: logit: async writer dropped 2 logs"
+	if len(errs) != 1 || errs[0] != want {
+		t.Fatalf("errs %+v != [%s]", errs, want)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriterTimeout$
+func TestAsyncWriterTimeout(t *testing.T) {
+	bw := newBlockWriter()
+	writer := Async(bw, WithDrainTimeout(10*time.Millisecond))
+
+	writer.Write([]byte("1"))
+	<-bw.started
+
+	if err := writer.Sync(); !errors.Is(err, ErrAsyncTimeout) {
+		t.Fatalf("err %+v isn't ErrAsyncTimeout", err)
+	}
+
+	if err := writer.Close(); !errors.Is(err, ErrAsyncTimeout) {
+		t.Fatalf("err %+v isn't ErrAsyncTimeout", err)
+	}
+
+	close(bw.unblock)
+	<-writer.done
+
+	if got := bw.String(); got != "1" {
+		t.Fatalf("got %s != 1", got)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriterFlushAndSync$
+func TestAsyncWriterFlushAndSync(t *testing.T) {
+	recorder := new(syncRecorder)
+
+	writer := Async(recorder)
+	defer writer.Close()
+
+	writer.Write([]byte("abc"))
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.String() != "abc" {
+		t.Fatalf("recorder.String() %s != abc", recorder.String())
+	}
+
+	if flushed, synced := recorder.flushed.Load(), recorder.synced.Load(); flushed != 1 || synced != 0 {
+		t.Fatalf("flushed %d != 1 || synced %d != 0", flushed, synced)
+	}
+
+	writer.Write([]byte("123"))
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.String() != "abc123" {
+		t.Fatalf("recorder.String() %s != abc123", recorder.String())
+	}
+
+	if flushed, synced := recorder.flushed.Load(), recorder.synced.Load(); flushed != 1 || synced != 1 {
+		t.Fatalf("flushed %d != 1 || synced %d != 1", flushed, synced)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestAsyncWriterWriteRacingClose$
+func TestAsyncWriterWriteRacingClose(t *testing.T) {
+	policies := []OverflowPolicy{OverflowBlock, OverflowDropNewest, OverflowDropOldest}
+
+	for _, policy := range policies {
+		buffer := new(lockedBuffer)
+		writer := Async(buffer, WithQueueSize(1024), WithOverflowPolicy(policy))
+
+		var written atomic.Int64
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for {
+					if _, err := writer.Write([]byte("x")); err != nil {
+						if err != ErrAsyncClosed {
+							t.Errorf("err %+v != ErrAsyncClosed", err)
+						}
+
+						return
+					}
+
+					written.Add(1)
+				}
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Wait()
+
+		// All successful writes should be written, and no drops happen since the queue is large enough for policies dropping data.
+		got := int64(len(buffer.String())) + int64(writer.Dropped())
+		if got != written.Load() {
+			t.Fatalf("policy %d: got %d != written %d", policy, got, written.Load())
+		}
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"io"
 	"sync"
+
+	"github.com/FishGoddess/logit/defaults"
 )
 
 const (
@@ -42,13 +44,20 @@ type BatchWriter struct {
 	// so you can pre-write them by Sync() if you want.
 	buffer *bytes.Buffer
 
+	// maxBytes is the max bytes of a batch, and 0 means no limit.
+	maxBytes uint64
+
+	// flusher flushes data in buffer in interval, and it's nil if flush interval isn't set.
+	flusher *flusher
+
 	lock sync.Mutex
 }
 
 // Batch returns a new batch writer of writer with specified batchSize.
 // Notice that batchSize must be larger than minBatchSize or a panic will happen.
 // See minBatchSize.
-func Batch(writer io.Writer, batchSize uint64) *BatchWriter {
+// Use WithFlushInterval to limit the time that data stays in batch, and use WithMaxBatchBytes to limit the bytes of batch.
+func Batch(writer io.Writer, batchSize uint64, opts ...Option) *BatchWriter {
 	if batchSize < minBatchSize {
 		panic(fmt.Errorf("logit: batchSize %d < minBatchSize %d", batchSize, minBatchSize))
 	}
@@ -64,39 +73,77 @@ func Batch(writer io.Writer, batchSize uint64) *BatchWriter {
 		buffer:         bytes.NewBuffer(make([]byte, 0, defaultBufferSize)),
 	}
 
+	conf := newConfig(opts)
+	bw.maxBytes = conf.maxBatchBytes
+	bw.flusher = startFlusher(conf.flushInterval, bw.flushInInterval)
 	return bw
 }
 
+// flushInInterval flushes data in batch and handles the error.
+func (bw *BatchWriter) flushInInterval() {
+	if err := bw.Flush(); err != nil {
+		defaults.HandleError("writer.BatchWriter.Flush", err)
+	}
+}
+
+// exceedBytes reports whether the batch will exceed max bytes after writing n bytes.
+func (bw *BatchWriter) exceedBytes(n int) bool {
+	if bw.maxBytes <= 0 || bw.buffer.Len() <= 0 {
+		return false
+	}
+
+	return uint64(bw.buffer.Len()+n) > bw.maxBytes
+}
+
 // Write writes p to buffer and syncs data to underlying writer first if it needs.
 func (bw *BatchWriter) Write(p []byte) (n int, err error) {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
-	if bw.currentBatches >= bw.maxBatches {
-		bw.sync()
-		bw.currentBatches = 0
+	if bw.currentBatches >= bw.maxBatches || bw.exceedBytes(len(p)) {
+		bw.flush()
 	}
 
 	bw.currentBatches++
 	return bw.buffer.Write(p)
 }
 
-func (bw *BatchWriter) sync() error {
+func (bw *BatchWriter) flush() error {
+	bw.currentBatches = 0
+
 	_, err := bw.buffer.WriteTo(bw.writer)
 	return err
 }
 
-// Sync writes data in buffer to underlying writer if buffer has data.
+// Flush writes data in buffer to underlying writer if buffer has data, and then flushes underlying writer if it's a Flusher.
+// Data may be still in memory of the os after flushing, so use Sync if you want them to be durable.
+// It's safe in concurrency.
+func (bw *BatchWriter) Flush() error {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	if bw.buffer.Len() > 0 {
+		if err := bw.flush(); err != nil {
+			return err
+		}
+	}
+
+	return flushWriter(bw.writer)
+}
+
+// Sync writes data in buffer to underlying writer, and then syncs underlying writer if it's a Syncer, like os.File.
 // It's safe in concurrency.
 func (bw *BatchWriter) Sync() error {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
 	if bw.buffer.Len() > 0 {
-		return bw.sync()
+		if err := bw.flush(); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return syncWriter(bw.writer)
 }
 
 func (bw *BatchWriter) close() error {
@@ -107,12 +154,15 @@ func (bw *BatchWriter) close() error {
 	return nil
 }
 
-// Close syncs data and closes underlying writer if writer implements io.Closer.
+// Close stops the flusher, flushes data and closes underlying writer if writer implements io.Closer.
 func (bw *BatchWriter) Close() error {
+	// Stop flusher before locking since it may be waiting for the lock.
+	bw.flusher.Stop()
+
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
-	if err := bw.sync(); err != nil {
+	if err := bw.flush(); err != nil {
 		return err
 	}
 
@@ -115,3 +115,102 @@ func TestBatchWriterClose(t *testing.T) {
 		}
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBatchWriterFlushInterval$
+func TestBatchWriterFlushInterval(t *testing.T) {
+	buffer := new(lockedBuffer)
+
+	writer := Batch(buffer, 10, WithFlushInterval(20*time.Millisecond))
+	defer writer.Close()
+
+	writer.Write([]byte("abc"))
+	if buffer.String() != "" {
+		t.Fatalf("buffer.String() %s != ''", buffer.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if buffer.String() != "abc" {
+		t.Fatalf("buffer.String() %s != abc", buffer.String())
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-writer.flusher.done:
+	default:
+		t.Fatal("flusher should be stopped after closing")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBatchWriterMaxBytes$
+func TestBatchWriterMaxBytes(t *testing.T) {
+	buffer := bytes.NewBuffer(make([]byte, 0, 4096))
+
+	writer := Batch(buffer, 10, WithMaxBatchBytes(8))
+	defer writer.Close()
+
+	writer.Write([]byte("1234"))
+	writer.Write([]byte("5678"))
+
+	if buffer.String() != "" {
+		t.Fatalf("buffer.String() %s != ''", buffer.String())
+	}
+
+	writer.Write([]byte("9"))
+	if buffer.String() != "12345678" {
+		t.Fatalf("buffer.String() %s != 12345678", buffer.String())
+	}
+
+	// A data larger than max bytes will be a batch itself.
+	writer.Write([]byte("0123456789"))
+	if buffer.String() != "123456789" {
+		t.Fatalf("buffer.String() %s != 123456789", buffer.String())
+	}
+
+	writer.Sync()
+	if buffer.String() != "1234567890123456789" {
+		t.Fatalf("buffer.String() %s != 1234567890123456789", buffer.String())
+	}
+
+	if writer.currentBatches != 0 {
+		t.Fatalf("writer.currentBatches %d != 0", writer.currentBatches)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBatchWriterFlushAndSync$
+func TestBatchWriterFlushAndSync(t *testing.T) {
+	recorder := new(syncRecorder)
+
+	writer := Batch(recorder, 16)
+	defer writer.Close()
+
+	writer.Write([]byte("abc"))
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.String() != "abc" {
+		t.Fatalf("recorder.String() %s != abc", recorder.String())
+	}
+
+	if flushed, synced := recorder.flushed.Load(), recorder.synced.Load(); flushed != 1 || synced != 0 {
+		t.Fatalf("flushed %d != 1 || synced %d != 0", flushed, synced)
+	}
+
+	writer.Write([]byte("123"))
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.String() != "abc123" {
+		t.Fatalf("recorder.String() %s != abc123", recorder.String())
+	}
+
+	if flushed, synced := recorder.flushed.Load(), recorder.synced.Load(); flushed != 1 || synced != 1 {
+		t.Fatalf("flushed %d != 1 || synced %d != 1", flushed, synced)
+	}
+}
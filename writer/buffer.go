@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"io"
 	"sync"
+
+	"github.com/FishGoddess/logit/defaults"
 )
 
 const (
@@ -40,13 +42,17 @@ type BufferWriter struct {
 	// so you can pre-write them by Sync() if you need.
 	buffer *bytes.Buffer
 
+	// flusher flushes data in buffer in interval, and it's nil if flush interval isn't set.
+	flusher *flusher
+
 	lock sync.Mutex
 }
 
 // Buffer returns a new buffer writer of writer with specified bufferSize.
 // Notice that bufferSize must be larger than minBufferSize or a panic will happen.
 // See minBufferSize.
-func Buffer(writer io.Writer, bufferSize uint64) *BufferWriter {
+// Use WithFlushInterval to limit the time that data stays in buffer.
+func Buffer(writer io.Writer, bufferSize uint64, opts ...Option) *BufferWriter {
 	if bufferSize < minBufferSize {
 		panic(fmt.Errorf("bufferSize %d < minBufferSize %d", bufferSize, minBufferSize))
 	}
@@ -61,9 +67,18 @@ func Buffer(writer io.Writer, bufferSize uint64) *BufferWriter {
 		buffer:        bytes.NewBuffer(make([]byte, 0, bufferSize)),
 	}
 
+	conf := newConfig(opts)
+	bw.flusher = startFlusher(conf.flushInterval, bw.flushInInterval)
 	return bw
 }
 
+// flushInInterval flushes data in buffer and handles the error.
+func (bw *BufferWriter) flushInInterval() {
+	if err := bw.Flush(); err != nil {
+		defaults.HandleError("writer.BufferWriter.Flush", err)
+	}
+}
+
 // Write writes p to buffer and syncs data to underlying writer first if it needs.
 func (bw *BufferWriter) Write(p []byte) (n int, err error) {
 	bw.lock.Lock()
@@ -73,7 +88,7 @@ func (bw *BufferWriter) Write(p []byte) (n int, err error) {
 	needBufferSize := len(p)
 	tooLarge := uint64(needBufferSize) >= bw.maxBufferSize
 	if tooLarge {
-		bw.sync()
+		bw.flush()
 		return bw.writer.Write(p)
 	}
 
@@ -81,28 +96,46 @@ func (bw *BufferWriter) Write(p []byte) (n int, err error) {
 	needBufferSize = bw.buffer.Len() + len(p)
 	notEnough := uint64(needBufferSize) >= bw.maxBufferSize
 	if notEnough {
-		bw.sync()
+		bw.flush()
 	}
 
 	return bw.buffer.Write(p)
 }
 
-func (bw *BufferWriter) sync() error {
+func (bw *BufferWriter) flush() error {
 	_, err := bw.buffer.WriteTo(bw.writer)
 	return err
 }
 
-// Sync writes data in buffer to underlying writer if buffer has data.
+// Flush writes data in buffer to underlying writer if buffer has data, and then flushes underlying writer if it's a Flusher.
+// Data may be still in memory of the os after flushing, so use Sync if you want them to be durable.
+// It's safe in concurrency.
+func (bw *BufferWriter) Flush() error {
+	bw.lock.Lock()
+	defer bw.lock.Unlock()
+
+	if bw.buffer.Len() > 0 {
+		if err := bw.flush(); err != nil {
+			return err
+		}
+	}
+
+	return flushWriter(bw.writer)
+}
+
+// Sync writes data in buffer to underlying writer, and then syncs underlying writer if it's a Syncer, like os.File.
 // It's safe in concurrency.
 func (bw *BufferWriter) Sync() error {
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
 	if bw.buffer.Len() > 0 {
-		return bw.sync()
+		if err := bw.flush(); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return syncWriter(bw.writer)
 }
 
 func (bw *BufferWriter) close() error {
@@ -113,12 +146,15 @@ func (bw *BufferWriter) close() error {
 	return nil
 }
 
-// Close syncs data and closes underlying writer if writer implements io.Closer.
+// Close stops the flusher, flushes data and closes underlying writer if writer implements io.Closer.
 func (bw *BufferWriter) Close() error {
+	// Stop flusher before locking since it may be waiting for the lock.
+	bw.flusher.Stop()
+
 	bw.lock.Lock()
 	defer bw.lock.Unlock()
 
-	if err := bw.sync(); err != nil {
+	if err := bw.flush(); err != nil {
 		return err
 	}
 
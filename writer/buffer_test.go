@@ -80,3 +80,72 @@ func TestBufferWriterClose(t *testing.T) {
 		}
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBufferWriterFlushInterval$
+func TestBufferWriterFlushInterval(t *testing.T) {
+	buffer := new(lockedBuffer)
+
+	writer := Buffer(buffer, 4096, WithFlushInterval(20*time.Millisecond))
+	defer writer.Close()
+
+	writer.Write([]byte("abc"))
+	if buffer.String() != "" {
+		t.Fatalf("buffer.String() %s != ''", buffer.String())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if buffer.String() != "abc" {
+		t.Fatalf("buffer.String() %s != abc", buffer.String())
+	}
+
+	writer.Write([]byte("123"))
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-writer.flusher.done:
+	default:
+		t.Fatal("flusher should be stopped after closing")
+	}
+
+	if buffer.String() != "abc123" {
+		t.Fatalf("buffer.String() %s != abc123", buffer.String())
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestBufferWriterFlushAndSync$
+func TestBufferWriterFlushAndSync(t *testing.T) {
+	recorder := new(syncRecorder)
+
+	writer := Buffer(recorder, 4096)
+	defer writer.Close()
+
+	writer.Write([]byte("abc"))
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.String() != "abc" {
+		t.Fatalf("recorder.String() %s != abc", recorder.String())
+	}
+
+	if flushed, synced := recorder.flushed.Load(), recorder.synced.Load(); flushed != 1 || synced != 0 {
+		t.Fatalf("flushed %d != 1 || synced %d != 0", flushed, synced)
+	}
+
+	writer.Write([]byte("123"))
+
+	if err := writer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.String() != "abc123" {
+		t.Fatalf("recorder.String() %s != abc123", recorder.String())
+	}
+
+	if flushed, synced := recorder.flushed.Load(), recorder.synced.Load(); flushed != 1 || synced != 1 {
+		t.Fatalf("flushed %d != 1 || synced %d != 1", flushed, synced)
+	}
+}
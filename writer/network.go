@@ -0,0 +1,433 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FishGoddess/logit/defaults"
+)
+
+const (
+	defaultDialTimeout     = 5 * time.Second
+	defaultWriteTimeout    = 5 * time.Second
+	defaultMinBackoff      = 100 * time.Millisecond
+	defaultMaxBackoff      = 30 * time.Second
+	defaultMaxPendingBytes = 4 * 1024 * 1024 // 4MB
+)
+
+var (
+	// ErrNetworkClosed is the error returned when writing to a closed network writer.
+	ErrNetworkClosed = errors.New("logit: network writer has been closed")
+
+	// ErrNetworkDisconnected is the error returned when flushing a network writer which is waiting to reconnect.
+	ErrNetworkDisconnected = errors.New("logit: network writer is disconnected")
+)
+
+type networkConfig struct {
+	dialTimeout     time.Duration
+	writeTimeout    time.Duration
+	minBackoff      time.Duration
+	maxBackoff      time.Duration
+	maxPendingBytes uint64
+	tlsConfig       *tls.Config
+}
+
+// NetworkOption is a function for setting network config.
+type NetworkOption func(conf *networkConfig)
+
+func (no NetworkOption) applyTo(conf *networkConfig) {
+	no(conf)
+}
+
+// WithDialTimeout sets the timeout of connecting to network config.
+func WithDialTimeout(timeout time.Duration) NetworkOption {
+	return func(conf *networkConfig) {
+		conf.dialTimeout = timeout
+	}
+}
+
+// WithWriteTimeout sets the timeout of writing to network config, so a slow peer won't block logging indefinitely.
+func WithWriteTimeout(timeout time.Duration) NetworkOption {
+	return func(conf *networkConfig) {
+		conf.writeTimeout = timeout
+	}
+}
+
+// WithBackoff sets the backoff of reconnecting to network config.
+// The backoff starts from min and doubles after every failure until it reaches max.
+func WithBackoff(min time.Duration, max time.Duration) NetworkOption {
+	return func(conf *networkConfig) {
+		conf.minBackoff = min
+		conf.maxBackoff = max
+	}
+}
+
+// WithMaxPendingBytes sets the max bytes of data kept while disconnected to network config.
+// The oldest data will be dropped if pending data exceed it.
+func WithMaxPendingBytes(maxBytes uint64) NetworkOption {
+	return func(conf *networkConfig) {
+		conf.maxPendingBytes = maxBytes
+	}
+}
+
+// WithTLS sets the tls config to network config, and the writer will connect with tls.
+// Only tcp networks support tls.
+func WithTLS(tlsConfig *tls.Config) NetworkOption {
+	return func(conf *networkConfig) {
+		conf.tlsConfig = tlsConfig
+	}
+}
+
+func newNetworkConfig(opts []NetworkOption) *networkConfig {
+	conf := &networkConfig{
+		dialTimeout:     defaultDialTimeout,
+		writeTimeout:    defaultWriteTimeout,
+		minBackoff:      defaultMinBackoff,
+		maxBackoff:      defaultMaxBackoff,
+		maxPendingBytes: defaultMaxPendingBytes,
+		tlsConfig:       nil,
+	}
+
+	for _, opt := range opts {
+		opt.applyTo(conf)
+	}
+
+	if conf.maxBackoff < conf.minBackoff {
+		conf.maxBackoff = conf.minBackoff
+	}
+
+	return conf
+}
+
+// NetworkWriter is a writer shipping logs to a tcp, udp or unix socket.
+// Every log is sent as a line in stream networks and as a datagram in datagram networks.
+// It reconnects with exponential backoff in a background goroutine when the connection is broken,
+// and keeps a bounded amount of data while disconnected, so writing never waits for connecting.
+type NetworkWriter struct {
+	network string
+	address string
+	conf    *networkConfig
+
+	conn net.Conn
+
+	// pending are the logs waiting to be sent while disconnected.
+	pending      [][]byte
+	pendingBytes uint64
+	dropped      uint64
+	reported     uint64
+
+	// reconnect wakes up the reconnector, and closing is closed when closing.
+	// done is closed after the reconnector exits.
+	reconnect chan struct{}
+	closing   chan struct{}
+	done      chan struct{}
+	closed    bool
+
+	lock sync.Mutex
+}
+
+// Network returns a new network writer of network and address and starts its reconnector.
+// Networks supported are "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix" and "unixgram".
+// It connects in background, so the peer doesn't need to be ready when creating and logs are pending until connected.
+// Remember closing the network writer, or the reconnector goroutine won't exit.
+func Network(network string, address string, opts ...NetworkOption) (*NetworkWriter, error) {
+	conf := newNetworkConfig(opts)
+
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	case "udp", "udp4", "udp6", "unix", "unixgram":
+		if conf.tlsConfig != nil {
+			return nil, fmt.Errorf("logit: network %s doesn't support tls", network)
+		}
+	default:
+		return nil, fmt.Errorf("logit: network %s unknown", network)
+	}
+
+	nw := &NetworkWriter{
+		network:   network,
+		address:   address,
+		conf:      conf,
+		reconnect: make(chan struct{}, 1),
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	nw.reconnect <- struct{}{}
+
+	go nw.runReconnector()
+	return nw, nil
+}
+
+// stream reports whether the network is a stream network, and logs in stream networks are framed by line breaks.
+func (nw *NetworkWriter) stream() bool {
+	return !strings.HasPrefix(nw.network, "udp") && nw.network != "unixgram"
+}
+
+// frame returns p framed in network.
+func (nw *NetworkWriter) frame(p []byte) []byte {
+	if nw.stream() && !bytes.HasSuffix(p, []byte("\n")) {
+		frame := make([]byte, len(p), len(p)+1)
+		copy(frame, p)
+		return append(frame, '\n')
+	}
+
+	return p
+}
+
+func (nw *NetworkWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: nw.conf.dialTimeout}
+
+	if nw.conf.tlsConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: nw.conf.tlsConfig}
+		return tlsDialer.Dial(nw.network, nw.address)
+	}
+
+	return dialer.Dial(nw.network, nw.address)
+}
+
+// connected sets conn as the connection and sends all pending frames.
+// It returns false if pending frames can't be sent, and the connection will be dropped.
+func (nw *NetworkWriter) connected(conn net.Conn) bool {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	// The connection isn't needed if the writer is closed or connected already.
+	if nw.closed || nw.conn != nil {
+		conn.Close()
+		return true
+	}
+
+	// Signals of reconnecting sent before connecting are stale, or the reconnector will connect again.
+	select {
+	case <-nw.reconnect:
+	default:
+	}
+
+	nw.conn = conn
+
+	if nw.dropped > nw.reported {
+		defaults.HandleError("writer.NetworkWriter", fmt.Errorf("logit: network writer dropped %d logs while disconnected", nw.dropped-nw.reported))
+		nw.reported = nw.dropped
+	}
+
+	return nw.flush() == nil
+}
+
+// runReconnector connects to the address in background when woken up, and retries with exponential backoff if failed.
+func (nw *NetworkWriter) runReconnector() {
+	defer close(nw.done)
+
+	for {
+		select {
+		case <-nw.reconnect:
+		case <-nw.closing:
+			return
+		}
+
+		var backoff time.Duration
+		for {
+			conn, err := nw.dial()
+			if err == nil && nw.connected(conn) {
+				break
+			}
+
+			// Only report the first failure of reconnecting so errors won't flood.
+			if err != nil && backoff <= 0 {
+				defaults.HandleError("writer.NetworkWriter.dial", err)
+			}
+
+			backoff = min(max(backoff*2, nw.conf.minBackoff), nw.conf.maxBackoff)
+			timer := time.NewTimer(backoff)
+
+			select {
+			case <-timer.C:
+			case <-nw.closing:
+				timer.Stop()
+				return
+			}
+		}
+	}
+}
+
+// disconnect closes the broken connection and wakes up the reconnector.
+func (nw *NetworkWriter) disconnect(err error) {
+	defaults.HandleError("writer.NetworkWriter.Write", err)
+
+	nw.conn.Close()
+	nw.conn = nil
+
+	select {
+	case nw.reconnect <- struct{}{}:
+	default:
+	}
+}
+
+// send sends frame and returns the count of bytes sent.
+func (nw *NetworkWriter) send(frame []byte) (int, error) {
+	if nw.conf.writeTimeout > 0 {
+		if err := nw.conn.SetWriteDeadline(time.Now().Add(nw.conf.writeTimeout)); err != nil {
+			return 0, err
+		}
+	}
+
+	return nw.conn.Write(frame)
+}
+
+// enqueue keeps frame in pending and drops the oldest frames if pending data exceed max pending bytes.
+func (nw *NetworkWriter) enqueue(frame []byte) {
+	nw.pending = append(nw.pending, frame)
+	nw.pendingBytes += uint64(len(frame))
+
+	for nw.pendingBytes > nw.conf.maxPendingBytes && len(nw.pending) > 0 {
+		nw.pendingBytes -= uint64(len(nw.pending[0]))
+		nw.pending[0] = nil
+		nw.pending = nw.pending[1:]
+		nw.dropped++
+	}
+}
+
+// flush sends all pending frames if connected.
+// Only the unsent tail of a frame is kept if it's sent partially, so the peer won't receive duplicated data.
+func (nw *NetworkWriter) flush() error {
+	if nw.conn == nil {
+		if len(nw.pending) > 0 {
+			return ErrNetworkDisconnected
+		}
+
+		return nil
+	}
+
+	for len(nw.pending) > 0 {
+		frame := nw.pending[0]
+
+		n, err := nw.send(frame)
+		nw.pendingBytes -= uint64(n)
+
+		if err != nil {
+			nw.pending[0] = frame[n:]
+			nw.disconnect(err)
+			return err
+		}
+
+		nw.pending[0] = nil
+		nw.pending = nw.pending[1:]
+	}
+
+	return nil
+}
+
+// Write sends p to the address.
+// It keeps p in pending and returns len(p) if disconnected, so handlers won't treat disconnections as errors.
+// It never connects, so logging won't wait for the peer even if it's down.
+func (nw *NetworkWriter) Write(p []byte) (n int, err error) {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	if nw.closed {
+		return 0, ErrNetworkClosed
+	}
+
+	frame := nw.frame(p)
+	if nw.conn != nil && len(nw.pending) <= 0 {
+		sent, err := nw.send(frame)
+		if err == nil {
+			return len(p), nil
+		}
+
+		// Keep the unsent tail only, and the broken connection won't be used to send the rest.
+		frame = frame[sent:]
+		nw.disconnect(err)
+	}
+
+	// Keep a copy of frame since p may be reused after returning.
+	nw.enqueue(bytes.Clone(frame))
+	return len(p), nil
+}
+
+// Dropped returns the count of logs dropped while disconnected.
+func (nw *NetworkWriter) Dropped() uint64 {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	return nw.dropped
+}
+
+// Flush sends all pending data to the address.
+// It returns ErrNetworkDisconnected if there are pending data while disconnected.
+func (nw *NetworkWriter) Flush() error {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	if nw.closed {
+		return nil
+	}
+
+	return nw.flush()
+}
+
+// Sync sends all pending data to the address like Flush since networks have no stable storage.
+func (nw *NetworkWriter) Sync() error {
+	return nw.Flush()
+}
+
+// Close stops the reconnector, sends all pending data and closes the connection.
+// It tries to connect once if disconnected with pending data, and pending data will be dropped if failed.
+func (nw *NetworkWriter) Close() error {
+	nw.lock.Lock()
+
+	if nw.closed {
+		nw.lock.Unlock()
+		return nil
+	}
+
+	nw.closed = true
+	nw.lock.Unlock()
+
+	close(nw.closing)
+	<-nw.done
+
+	// Writing is rejected after closed, so we can connect without holding the lock.
+	if nw.conn == nil && len(nw.pending) > 0 {
+		conn, err := nw.dial()
+		if err != nil {
+			return err
+		}
+
+		nw.conn = conn
+	}
+
+	if nw.conn == nil {
+		return nil
+	}
+
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	if err := nw.flush(); err != nil {
+		return err
+	}
+
+	err := nw.conn.Close()
+	nw.conn = nil
+	return err
+}
@@ -0,0 +1,322 @@
+// Copyright 2025 FishGoddess. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetwork$
+func TestNetwork(t *testing.T) {
+	if _, err := Network("tcp", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Network("http", "127.0.0.1:0"); err == nil {
+		t.Fatal("creating network writer of http should be failed")
+	}
+
+	if _, err := Network("udp", "127.0.0.1:0", WithTLS(new(tls.Config))); err == nil {
+		t.Fatal("creating network writer of udp with tls should be failed")
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetworkWriterTCP$
+func TestNetworkWriterTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	writer, err := Network("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer writer.Close()
+
+	writer.Write([]byte("abc\n"))
+	writer.Write([]byte("123"))
+
+	for _, want := range []string{"abc", "123"} {
+		select {
+		case line := <-lines:
+			if line != want {
+				t.Fatalf("line %s != want %s", line, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("line %s not received", want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetworkWriterUDP$
+func TestNetworkWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	writer, err := Network("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer writer.Close()
+
+	writer.Write([]byte("abc"))
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	buffer := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Datagrams are framed by themselves, so no line breaks should be appended.
+	if string(buffer[:n]) != "abc" {
+		t.Fatalf("string(buffer[:n]) %s != abc", buffer[:n])
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetworkWriterReconnect$
+func TestNetworkWriterReconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.sock")
+
+	writer, err := Network("unix", path, WithBackoff(time.Millisecond, 10*time.Millisecond), WithMaxPendingBytes(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer writer.Close()
+
+	// The socket isn't listened, so logs are pending and the oldest one will be dropped.
+	writer.Write([]byte("abc"))
+	writer.Write([]byte("def"))
+	writer.Write([]byte("ghi"))
+
+	if dropped := writer.Dropped(); dropped != 1 {
+		t.Fatalf("dropped %d != 1", dropped)
+	}
+
+	if err = writer.Flush(); !errors.Is(err, ErrNetworkDisconnected) {
+		t.Fatalf("err %+v isn't ErrNetworkDisconnected", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+
+	if err = writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if dropped := writer.Dropped(); dropped != 1 {
+		t.Fatalf("dropped %d != 1", dropped)
+	}
+
+	for _, want := range []string{"def", "ghi"} {
+		select {
+		case line := <-lines:
+			if line != want {
+				t.Fatalf("line %s != want %s", line, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("line %s not received", want)
+		}
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetworkWriterClose$
+func TestNetworkWriterClose(t *testing.T) {
+	writer, err := Network("unix", filepath.Join(t.TempDir(), "test.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer.Write([]byte("abc"))
+
+	if err = writer.Close(); err == nil {
+		t.Fatal("closing a disconnected writer with pending logs should be failed")
+	}
+
+	if _, err = writer.Write([]byte("abc")); err != ErrNetworkClosed {
+		t.Fatalf("err %+v != ErrNetworkClosed", err)
+	}
+
+	if err = writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// partialConn is a connection which sends limit bytes and then fails.
+type partialConn struct {
+	net.Conn
+
+	sent   bytes.Buffer
+	limit  int
+	closed bool
+}
+
+func (pc *partialConn) Write(p []byte) (n int, err error) {
+	n = min(len(p), pc.limit)
+	pc.sent.Write(p[:n])
+	pc.limit -= n
+
+	if n < len(p) {
+		return n, errors.New("partial write")
+	}
+
+	return n, nil
+}
+
+func (pc *partialConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func (pc *partialConn) Close() error {
+	pc.closed = true
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetworkWriterPartialWrite$
+func TestNetworkWriterPartialWrite(t *testing.T) {
+	conn := &partialConn{limit: 6}
+
+	writer := &NetworkWriter{
+		network:   "tcp",
+		conf:      newNetworkConfig(nil),
+		conn:      conn,
+		reconnect: make(chan struct{}, 1),
+	}
+
+	writer.Write([]byte("abc"))
+	writer.Write([]byte("def"))
+
+	if conn.sent.String() != "abc\nde" {
+		t.Fatalf("conn.sent.String() %q != %q", conn.sent.String(), "abc\nde")
+	}
+
+	if writer.conn != nil {
+		t.Fatal("writer.conn != nil")
+	}
+
+	// Only the unsent tail is pending, so the peer won't receive duplicated data after reconnecting.
+	if len(writer.pending) != 1 || string(writer.pending[0]) != "f\n" {
+		t.Fatalf("writer.pending %q is wrong", writer.pending)
+	}
+
+	if writer.pendingBytes != 2 {
+		t.Fatalf("writer.pendingBytes %d != 2", writer.pendingBytes)
+	}
+
+	conn = &partialConn{limit: 1}
+	writer.conn = conn
+
+	if err := writer.Flush(); err == nil {
+		t.Fatal("flushing to a broken connection should be failed")
+	}
+
+	if len(writer.pending) != 1 || string(writer.pending[0]) != "\n" || writer.pendingBytes != 1 {
+		t.Fatalf("writer.pending %q is wrong", writer.pending)
+	}
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestNetworkWriterConnected$
+func TestNetworkWriterConnected(t *testing.T) {
+	writer := &NetworkWriter{
+		network:   "tcp",
+		conf:      newNetworkConfig(nil),
+		reconnect: make(chan struct{}, 1),
+	}
+
+	writer.Write([]byte("abc"))
+
+	// Flushing to a broken connection wakes up the reconnector.
+	if writer.connected(&partialConn{limit: 0}) {
+		t.Fatal("connected to a broken connection should be failed")
+	}
+
+	if len(writer.reconnect) != 1 {
+		t.Fatalf("len(writer.reconnect) %d != 1", len(writer.reconnect))
+	}
+
+	conn := &partialConn{limit: 1024}
+	if !writer.connected(conn) {
+		t.Fatal("connected should be successful")
+	}
+
+	// The signal sent before connecting is stale, so the reconnector won't connect again.
+	if len(writer.reconnect) != 0 {
+		t.Fatalf("len(writer.reconnect) %d != 0", len(writer.reconnect))
+	}
+
+	if conn.sent.String() != "abc\n" {
+		t.Fatalf("conn.sent.String() %q != %q", conn.sent.String(), "abc\n")
+	}
+
+	// A connection made when connected already should be closed.
+	newConn := &partialConn{limit: 1024}
+	if !writer.connected(newConn) || !newConn.closed || writer.conn != conn {
+		t.Fatalf("newConn %+v isn't closed", newConn)
+	}
+}
@@ -17,13 +17,142 @@ package writer
 import (
 	"io"
 	"os"
+	"sync"
+	"time"
 )
 
 const (
 	defaultBufferSize = 64 * 1024 // 64KB
 )
 
+type config struct {
+	flushInterval time.Duration
+	maxBatchBytes uint64
+}
+
+// Option is a function for setting config of buffer writer and batch writer.
+type Option func(conf *config)
+
+func (o Option) applyTo(conf *config) {
+	o(conf)
+}
+
+// WithFlushInterval sets the flush interval to config.
+// Data will be written to underlying writer in interval even if the buffer isn't full,
+// so no data stays in memory longer than interval.
+// A background goroutine will be started if interval > 0, and it exits after closing the writer.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(conf *config) {
+		conf.flushInterval = interval
+	}
+}
+
+// WithMaxBatchBytes sets the max bytes of a batch to config.
+// A batch will be written to underlying writer if its size will exceed maxBytes even if the batch size isn't reached.
+// Only available in batch writer.
+func WithMaxBatchBytes(maxBytes uint64) Option {
+	return func(conf *config) {
+		conf.maxBatchBytes = maxBytes
+	}
+}
+
+func newConfig(opts []Option) *config {
+	conf := &config{
+		flushInterval: 0,
+		maxBatchBytes: 0,
+	}
+
+	for _, opt := range opts {
+		opt.applyTo(conf)
+	}
+
+	return conf
+}
+
+// flusher flushes data in interval by a background goroutine.
+type flusher struct {
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startFlusher starts a flusher calling flush in interval.
+// It returns nil if interval <= 0.
+func startFlusher(interval time.Duration, flush func()) *flusher {
+	if interval <= 0 {
+		return nil
+	}
+
+	f := &flusher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(f.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				flush()
+			case <-f.stop:
+				return
+			}
+		}
+	}()
+
+	return f
+}
+
+// Stop stops the flusher and waits for its goroutine to exit.
+// It's safe to call it on a nil flusher or to call it more than once.
+func (f *flusher) Stop() {
+	if f == nil {
+		return
+	}
+
+	f.stopOnce.Do(func() {
+		close(f.stop)
+	})
+
+	<-f.done
+}
+
 // notStdoutAndStderr returns true if w isn't stdout and stderr.
 func notStdoutAndStderr(w io.Writer) bool {
 	return w != os.Stdout && w != os.Stderr
 }
+
+// Flusher is a writer which keeps data in memory and can flush them to its underlying writer.
+// Flushing doesn't make data durable since they may be still in memory of the os.
+type Flusher interface {
+	Flush() error
+}
+
+// Syncer is a writer which can sync data to stable storage, like os.File.
+// Writers wrapping other writers should flush their data and then sync the underlying writers.
+type Syncer interface {
+	Sync() error
+}
+
+// flushWriter flushes w if w is a flusher.
+func flushWriter(w io.Writer) error {
+	if flusher, ok := w.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+// syncWriter syncs w if w is a syncer.
+// Stdout and stderr won't be synced since they may be terminals or pipes which don't support syncing.
+func syncWriter(w io.Writer) error {
+	if syncer, ok := w.(Syncer); ok && notStdoutAndStderr(w) {
+		return syncer.Sync()
+	}
+
+	return nil
+}
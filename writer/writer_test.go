@@ -15,10 +15,81 @@
 package writer
 
 import (
+	"bytes"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
+// lockedBuffer is a buffer safe in concurrency for testing flushers.
+type lockedBuffer struct {
+	buffer bytes.Buffer
+	lock   sync.Mutex
+}
+
+func (lb *lockedBuffer) Write(p []byte) (n int, err error) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+
+	return lb.buffer.Write(p)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+
+	return lb.buffer.String()
+}
+
+// syncRecorder records data written and the count of flushing and syncing.
+type syncRecorder struct {
+	lockedBuffer
+
+	flushed atomic.Int64
+	synced  atomic.Int64
+}
+
+func (sr *syncRecorder) Flush() error {
+	sr.flushed.Add(1)
+	return nil
+}
+
+func (sr *syncRecorder) Sync() error {
+	sr.synced.Add(1)
+	return nil
+}
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFlushWriterAndSyncWriter$
+func TestFlushWriterAndSyncWriter(t *testing.T) {
+	recorder := new(syncRecorder)
+
+	if err := flushWriter(recorder); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncWriter(recorder); err != nil {
+		t.Fatal(err)
+	}
+
+	if flushed := recorder.flushed.Load(); flushed != 1 {
+		t.Fatalf("flushed %d != 1", flushed)
+	}
+
+	if synced := recorder.synced.Load(); synced != 1 {
+		t.Fatalf("synced %d != 1", synced)
+	}
+
+	if err := flushWriter(new(bytes.Buffer)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncWriter(os.Stdout); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // go test -v -cover -count=1 -test.cpu=1 -run=^TestNotStdoutAndStderr$
 func TestNotStdoutAndStderr(t *testing.T) {
 	if notStdoutAndStderr(os.Stdout) {
@@ -29,3 +100,32 @@ func TestNotStdoutAndStderr(t *testing.T) {
 		t.Fatal("notStdoutAndStderr(os.Stderr) returns true")
 	}
 }
+
+// go test -v -cover -count=1 -test.cpu=1 -run=^TestFlusher$
+func TestFlusher(t *testing.T) {
+	var nilFlusher *flusher
+	nilFlusher.Stop()
+
+	if f := startFlusher(0, func() {}); f != nil {
+		t.Fatalf("f %+v != nil", f)
+	}
+
+	var flushed atomic.Int64
+	f := startFlusher(10*time.Millisecond, func() {
+		flushed.Add(1)
+	})
+
+	time.Sleep(55 * time.Millisecond)
+	f.Stop()
+	f.Stop()
+
+	count := flushed.Load()
+	if count < 3 {
+		t.Fatalf("count %d < 3", count)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if flushed.Load() != count {
+		t.Fatalf("flushed %d != count %d after stopping", flushed.Load(), count)
+	}
+}